@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Claves del contexto de gin donde Middleware guarda el usuario autenticado y
+// la sesión de su token.
+const (
+	UserIDKey    = "userID"
+	SessionIDKey = "sessionID"
+)
+
+// Middleware verifica el JWT del header Authorization y que su sesión siga
+// activa, y guarda su sujeto en UserIDKey y la sesión en SessionIDKey. Con
+// legacyHeader se acepta además el header User-ID, pensado solo para
+// desarrollo local.
+func Middleware(verifier *Verifier, legacyHeader bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+
+		if header == "" && legacyHeader {
+			if userID := c.GetHeader("User-ID"); userID != "" {
+				c.Set(UserIDKey, userID)
+				c.Next()
+				return
+			}
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" || verifier == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token no proporcionado"})
+			return
+		}
+
+		claims, err := verifier.Verify(c.Request.Context(), token)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, ErrInvalidToken) {
+				status = http.StatusUnauthorized
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Set(UserIDKey, claims.Subject)
+		c.Set(SessionIDKey, claims.SessionID)
+		c.Next()
+	}
+}
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+// Config reúne los parámetros con los que se firman y validan los tokens.
+type Config struct {
+	Algorithm string
+	Secret    string
+	PublicKey string
+	Issuer    string
+}
+
+// ErrInvalidToken indica que el token no es válido, expiró o su sesión ya no
+// está activa.
+var ErrInvalidToken = errors.New("token inválido o expirado")
+
+// AccessToken identifica los tokens que autorizan las peticiones a los servicios.
+const AccessToken = "access"
+
+// touchSession devuelve el usuario de la sesión y registra su último uso,
+// sin recrear la sesión si se revocó o expiró entre tanto.
+var touchSession = redis.NewScript(`
+local userID = redis.call('HGET', KEYS[1], 'user_id')
+if userID then
+	redis.call('HSET', KEYS[1], 'last_seen_at', ARGV[1])
+end
+return userID
+`)
+
+// Claims son los datos firmados en cada token. El sujeto es el ID del usuario
+// y SessionID la sesión de la que depende el token.
+type Claims struct {
+	Type      string `json:"typ"`
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// Verifier comprueba la firma de los tokens y que su sesión siga activa en
+// el Redis compartido, sin consultar al user-service.
+type Verifier struct {
+	method jwt.SigningMethod
+	key    interface{}
+	issuer string
+	redis  *redis.Client
+}
+
+func NewVerifier(cfg Config, redis *redis.Client) (*Verifier, error) {
+	verifier := &Verifier{issuer: cfg.Issuer, redis: redis}
+
+	switch cfg.Algorithm {
+	case "HS256":
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("auth.secret (AUTH_SECRET) es obligatorio con HS256")
+		}
+		verifier.method = jwt.SigningMethodHS256
+		verifier.key = []byte(cfg.Secret)
+	case "EdDSA":
+		key, err := jwt.ParseEdPublicKeyFromPEM([]byte(cfg.PublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("error al leer auth.public_key: %w", err)
+		}
+		verifier.method = jwt.SigningMethodEdDSA
+		verifier.key = key
+	default:
+		return nil, fmt.Errorf("algoritmo de firma no soportado: %s", cfg.Algorithm)
+	}
+
+	return verifier, nil
+}
+
+// Parse valida la firma, el emisor y la expiración de un token de acceso.
+func (v *Verifier) Parse(token string) (*Claims, error) {
+	claims := &Claims{}
+
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return v.key, nil
+	},
+		jwt.WithValidMethods([]string{v.method.Alg()}),
+		jwt.WithIssuer(v.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || claims.Type != AccessToken || claims.Subject == "" || claims.SessionID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// Verify valida el token y rechaza los de sesiones revocadas o expiradas.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	claims, err := v.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.CheckSession(ctx, claims.SessionID, claims.Subject); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// CheckSession comprueba que la sesión siga activa y pertenezca al usuario.
+// Permite revalidar las conexiones largas, que solo presentan el token al
+// abrirse.
+func (v *Verifier) CheckSession(ctx context.Context, sessionID, userID string) error {
+	key := fmt.Sprintf("sessions:%s", sessionID)
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	sessionUserID, err := touchSession.Run(ctx, v.redis, []string{key}, now).Text()
+	if err == redis.Nil {
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return fmt.Errorf("error al consultar la sesión: %w", err)
+	}
+	if sessionUserID != userID {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
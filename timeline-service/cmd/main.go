@@ -1,8 +1,11 @@
 package main
 
 import (
+	"log"
+	"shared/auth"
 	"timeline-service/config"
 	"timeline-service/internal/application"
+	"timeline-service/internal/domain/ranking"
 	"timeline-service/internal/infrastructure/cron"
 	"timeline-service/internal/infrastructure/http"
 	"timeline-service/internal/infrastructure/repository"
@@ -18,17 +21,33 @@ func main() {
 	validate := validator.New()
 	redis := cfg.Redis()
 
-	precess := cron.NewCron(redis)
-
-	go precess.ProcessTweets()
+	// Estrategias de ranking entre las que se reparten los lectores
+	strategies := make([]ranking.Strategy, 0, len(cfg.Ranking.Strategies))
+	for _, name := range cfg.Ranking.Strategies {
+		strategy, ok := ranking.Lookup(name)
+		if !ok {
+			log.Fatalf("Estrategia de ranking desconocida: %s", name)
+		}
+		strategies = append(strategies, strategy)
+	}
 
 	// Inicializar repositorio
-	repo := repository.NewRepository(redis)
+	repo := repository.NewRepository(redis, cfg.TimelineLength, strategies, cfg.Ranking.Candidates)
+
+	precess := cron.NewCron(redis, cfg, repo)
+
+	go precess.ProcessTweets()
+	go precess.ProcessFollows()
 
 	// Inicializar servicios
 	service := application.NewService(repo)
 
-	httpServer := http.NewHTTPServer(engine, service, validate)
+	verifier, err := auth.NewVerifier(cfg.Auth.Config, redis)
+	if err != nil {
+		log.Fatalf("Error al configurar la autenticación: %v", err)
+	}
+
+	httpServer := http.NewHTTPServer(engine, service, validate, cfg.Admins, auth.Middleware(verifier, cfg.Auth.LegacyHeader), verifier)
 
 	httpServer.Run(cfg.Port)
 
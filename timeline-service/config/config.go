@@ -2,7 +2,12 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"os"
+	"shared/auth"
+	"strings"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/spf13/viper"
@@ -12,6 +17,64 @@ type Config struct {
 	Port         string
 	Env          string
 	RedisOptions *redis.Options
+	Stream       StreamConfig
+	Retry        RetryConfig
+	Admins       []string
+	Backfill     int
+	// FanoutThreshold es el número de seguidores a partir del cual los tweets
+	// de un autor no se distribuyen al escribir sino que se mezclan al leer
+	FanoutThreshold int
+	// TimelineLength es el número máximo de tweets que se guardan en cada
+	// timeline; las páginas más antiguas se reconstruyen al leer
+	TimelineLength int
+	Ranking        RankingConfig
+	Auth           AuthConfig
+}
+
+// RankingConfig controla el modo ranked del timeline. Los lectores se reparten
+// entre las estrategias indicadas para compararlas.
+type RankingConfig struct {
+	Strategies []string
+	// Candidates es el número de tweets recientes que se puntúan
+	Candidates int
+}
+
+// AuthConfig controla la verificación de los JWT que emite el user-service.
+// Con HS256 se usa Secret; con EdDSA, la clave pública Ed25519 en formato PEM.
+// Ambas se leen de las variables AUTH_SECRET y AUTH_PUBLIC_KEY.
+type AuthConfig struct {
+	auth.Config
+	// LegacyHeader acepta el header User-ID sin token, solo para desarrollo local
+	LegacyHeader bool
+}
+
+// RetryConfig controla los reintentos de distribución antes de enviar un tweet
+// a la cola de fallidos.
+type RetryConfig struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// TotalDelay es la espera máxima acumulada entre los reintentos de un tweet:
+// la espera se duplica desde BaseDelay sin superar MaxDelay, una vez entre
+// cada par de intentos.
+func (r RetryConfig) TotalDelay() time.Duration {
+	var total time.Duration
+	delay := r.BaseDelay
+	for attempt := 1; attempt < r.MaxAttempts; attempt++ {
+		total += delay
+		delay = min(delay*2, r.MaxDelay)
+	}
+	return total
+}
+
+// StreamConfig controla el consumo del stream de tweets nuevos.
+type StreamConfig struct {
+	Consumer     string
+	Workers      int
+	ClaimMinIdle time.Duration
+	Retention    time.Duration
 }
 
 func LoadConfig() *Config {
@@ -19,11 +82,35 @@ func LoadConfig() *Config {
 	viper.SetConfigType("yml")
 	viper.AddConfigPath(".")
 
+	viper.SetDefault("stream.workers", 5)
+	viper.SetDefault("stream.claim_min_idle", "1m")
+	viper.SetDefault("stream.retention", "24h")
+	viper.SetDefault("retry.max_attempts", 5)
+	viper.SetDefault("retry.base_delay", "500ms")
+	viper.SetDefault("retry.max_delay", "10s")
+	viper.SetDefault("follow.backfill", 20)
+	viper.SetDefault("fanout.follower_threshold", 10000)
+	viper.SetDefault("timeline.max_length", 800)
+	viper.SetDefault("ranking.strategies", []string{"balanced"})
+	viper.SetDefault("ranking.candidates", 200)
+	viper.SetDefault("auth.algorithm", "HS256")
+	viper.SetDefault("auth.issuer", "user-service")
+	viper.SetDefault("auth.legacy_header", false)
+	viper.BindEnv("auth.secret", "AUTH_SECRET")
+	viper.BindEnv("auth.public_key", "AUTH_PUBLIC_KEY")
+
 	if err := viper.ReadInConfig(); err != nil {
 		log.Fatalf("Error al leer la configuración: %v", err)
 	}
 
-	return &Config{
+	// Cada réplica necesita un nombre de consumidor único dentro del grupo
+	consumer := viper.GetString("stream.consumer")
+	if consumer == "" {
+		hostname, _ := os.Hostname()
+		consumer = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	cfg := &Config{
 		Port: viper.GetString("server.port"),
 		Env:  viper.GetString("env"),
 		RedisOptions: &redis.Options{
@@ -31,8 +118,93 @@ func LoadConfig() *Config {
 			Password: viper.GetString("db.redis.password"),
 			DB:       viper.GetInt("db.redis.db"),
 		},
+		Stream: StreamConfig{
+			Consumer:     consumer,
+			Workers:      viper.GetInt("stream.workers"),
+			ClaimMinIdle: viper.GetDuration("stream.claim_min_idle"),
+			Retention:    viper.GetDuration("stream.retention"),
+		},
+		Retry: RetryConfig{
+			MaxAttempts: viper.GetInt("retry.max_attempts"),
+			BaseDelay:   viper.GetDuration("retry.base_delay"),
+			MaxDelay:    viper.GetDuration("retry.max_delay"),
+		},
+		Admins:   admins(),
+		Backfill: viper.GetInt("follow.backfill"),
+
+		FanoutThreshold: viper.GetInt("fanout.follower_threshold"),
+		TimelineLength:  viper.GetInt("timeline.max_length"),
+		Ranking: RankingConfig{
+			Strategies: viper.GetStringSlice("ranking.strategies"),
+			Candidates: viper.GetInt("ranking.candidates"),
+		},
+		Auth: AuthConfig{
+			Config: auth.Config{
+				Algorithm: viper.GetString("auth.algorithm"),
+				Secret:    viper.GetString("auth.secret"),
+				PublicKey: viper.GetString("auth.public_key"),
+				Issuer:    viper.GetString("auth.issuer"),
+			},
+			LegacyHeader: viper.GetBool("auth.legacy_header"),
+		},
 	}
+
+	if err := cfg.validate(); err != nil {
+		log.Fatalf("Configuración inválida: %v", err)
+	}
+
+	return cfg
+}
+
+// admins devuelve los administradores de la variable de entorno ADMINS,
+// separados por comas, o en su defecto los de la clave admins.
+func admins() []string {
+	env := os.Getenv("ADMINS")
+	if env == "" {
+		return viper.GetStringSlice("admins")
+	}
+
+	var ids []string
+	for _, id := range strings.Split(env, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// validate rechaza los valores que dejarían al servicio bloqueado o sin
+// límites, en lugar de descubrirlo con el servicio en marcha.
+func (c *Config) validate() error {
+	if c.Stream.Workers <= 0 {
+		return fmt.Errorf("stream.workers debe ser mayor que 0")
+	}
+	// claim_min_idle es también el intervalo del ticker que reclama pendientes
+	if c.Stream.ClaimMinIdle <= 0 {
+		return fmt.Errorf("stream.claim_min_idle debe ser mayor que 0")
+	}
+	// La retención es la caducidad de las marcas processed_tweets:<id>
+	if c.Stream.Retention <= 0 {
+		return fmt.Errorf("stream.retention debe ser mayor que 0")
+	}
+	if c.Retry.MaxAttempts <= 0 {
+		return fmt.Errorf("retry.max_attempts debe ser mayor que 0")
+	}
+	if c.Retry.BaseDelay <= 0 || c.Retry.MaxDelay < c.Retry.BaseDelay {
+		return fmt.Errorf("retry.base_delay debe ser mayor que 0 y no superar retry.max_delay")
+	}
+	// La entrada sigue pendiente mientras se reintenta; si se reclamara antes,
+	// otra réplica la distribuiría por duplicado
+	if total := c.Retry.TotalDelay(); c.Stream.ClaimMinIdle <= total {
+		return fmt.Errorf("stream.claim_min_idle debe superar la espera total de los reintentos (%s)", total)
+	}
+	// Sin candidatos el modo ranked devolvería siempre un timeline vacío
+	if c.Ranking.Candidates <= 0 {
+		return fmt.Errorf("ranking.candidates debe ser mayor que 0")
+	}
+	return nil
 }
+
 func (c *Config) Redis() *redis.Client {
 	rdb := redis.NewClient(c.RedisOptions)
 
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryConfig_TotalDelay(t *testing.T) {
+	retry := RetryConfig{MaxAttempts: 5, BaseDelay: 500 * time.Millisecond, MaxDelay: 10 * time.Second}
+	assert.Equal(t, 7500*time.Millisecond, retry.TotalDelay())
+
+	// La espera no supera MaxDelay
+	retry = RetryConfig{MaxAttempts: 6, BaseDelay: time.Second, MaxDelay: 3 * time.Second}
+	assert.Equal(t, 12*time.Second, retry.TotalDelay())
+
+	// Sin reintentos no hay espera
+	retry.MaxAttempts = 1
+	assert.Equal(t, time.Duration(0), retry.TotalDelay())
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() *Config {
+		return &Config{
+			Stream:  StreamConfig{Workers: 1, ClaimMinIdle: time.Minute, Retention: time.Hour},
+			Retry:   RetryConfig{MaxAttempts: 5, BaseDelay: 500 * time.Millisecond, MaxDelay: 10 * time.Second},
+			Ranking: RankingConfig{Candidates: 200},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		valid  bool
+	}{
+		{"valores por defecto", func(*Config) {}, true},
+		{"sin workers", func(c *Config) { c.Stream.Workers = 0 }, false},
+		{"sin retención", func(c *Config) { c.Stream.Retention = 0 }, false},
+		{"base_delay mayor que max_delay", func(c *Config) { c.Retry.BaseDelay = time.Minute }, false},
+		{"sin candidatos", func(c *Config) { c.Ranking.Candidates = 0 }, false},
+		// Con 10 intentos la espera total es 0,5+1+2+4+8+10*4 = 55,5s
+		{"claim_min_idle mayor que los reintentos", func(c *Config) { c.Retry.MaxAttempts = 10 }, true},
+		{"claim_min_idle menor que los reintentos", func(c *Config) { c.Retry.MaxAttempts = 11 }, false},
+		{"claim_min_idle igual que los reintentos", func(c *Config) { c.Stream.ClaimMinIdle = 7500 * time.Millisecond }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(cfg)
+
+			err := cfg.validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
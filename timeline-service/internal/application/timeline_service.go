@@ -16,6 +16,22 @@ func NewService(repo interfaces.Repository) interfaces.Service {
 	}
 }
 
-func (s *timelineService) Paginate(ctx context.Context, id string, page, size int) ([]*models.Timeline, error) {
-	return s.repo.Paginate(ctx, id, page, size)
+func (s *timelineService) Paginate(ctx context.Context, id string, query *models.TimelineQuery) (*models.TimelinePage, error) {
+	return s.repo.Paginate(ctx, id, query)
+}
+
+func (s *timelineService) Stream(ctx context.Context, id, lastEventID string) (<-chan *models.TimelineEvent, error) {
+	return s.repo.Stream(ctx, id, lastEventID)
+}
+
+func (s *timelineService) DeadLetters(ctx context.Context, page, size int) ([]*models.DeadLetter, error) {
+	return s.repo.DeadLetters(ctx, page, size)
+}
+
+func (s *timelineService) ReplayDeadLetter(ctx context.Context, tweetID string) error {
+	return s.repo.ReplayDeadLetter(ctx, tweetID)
+}
+
+func (s *timelineService) DiscardDeadLetter(ctx context.Context, tweetID string) error {
+	return s.repo.DiscardDeadLetter(ctx, tweetID)
 }
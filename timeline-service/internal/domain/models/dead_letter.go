@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// DeadLetter es un tweet cuya distribución falló tras agotar los reintentos.
+type DeadLetter struct {
+	TweetID  string    `json:"tweetId"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// DeadLetterQuery pide una página de la cola de fallidos, numerada desde 1.
+type DeadLetterQuery struct {
+	Page int `form:"page" validate:"min=1"`
+	Size int `form:"size" validate:"min=1,max=100"`
+}
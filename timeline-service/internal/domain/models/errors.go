@@ -0,0 +1,9 @@
+package models
+
+import "errors"
+
+var (
+	ErrDeadLetterNotFound = errors.New("tweet fallido no encontrado")
+	ErrInvalidCursor      = errors.New("cursor inválido")
+	ErrRankingExpired     = errors.New("la clasificación del timeline expiró, vuelve a pedir la primera página")
+)
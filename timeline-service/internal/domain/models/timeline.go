@@ -1,12 +1,31 @@
 package models
 
+import "time"
+
+const (
+	TweetKindOriginal = "tweet"
+	TweetKindRetweet  = "retweet"
+	TweetKindQuote    = "quote"
+)
+
+const (
+	// TimelineModeChronological devuelve los tweets del más reciente al más antiguo
+	TimelineModeChronological = "chronological"
+	// TimelineModeRanked ordena los tweets recientes por relevancia para el lector
+	TimelineModeRanked = "ranked"
+)
+
 type Tweet struct {
-	ID       string `json:"id"`
-	UserID   string `json:"userId"`
-	Content  string `json:"content"`
-	Likes    int    `json:"likes"`
-	Shares   int    `json:"shares"`
-	Comments int    `json:"comments"`
+	ID          string `json:"id"`
+	UserID      string `json:"userId"`
+	Kind        string `json:"kind"`
+	ReferenceID string `json:"referenceId"`
+	Content     string `json:"content"`
+	Likes       int    `json:"likes"`
+	Shares      int    `json:"shares"`
+	Comments    int    `json:"comments"`
+
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type User struct {
@@ -18,6 +37,7 @@ type User struct {
 
 type Timeline struct {
 	ID       string `json:"id"`
+	Kind     string `json:"kind"`
 	Content  string `json:"content"`
 	Likes    int    `json:"likes"`
 	Shares   int    `json:"shares"`
@@ -27,4 +47,35 @@ type Timeline struct {
 	Name     string `json:"name"`
 	Nickname string `json:"nickname"`
 	Avatar   string `json:"avatar"`
+
+	// Tweet original embebido en retweets y citas
+	Original *Timeline `json:"original,omitempty"`
+}
+
+// TimelineQuery pide la página anterior (más antigua) a Before o la siguiente
+// (más reciente) a After. Sin cursores se devuelven los tweets más recientes.
+// En modo ranked Before pide la siguiente página por relevancia y After no
+// está disponible.
+type TimelineQuery struct {
+	Before string `form:"before"`
+	After  string `form:"after" validate:"excluded_with=Before,excluded_if=Mode ranked"`
+	Size   int    `form:"size" validate:"omitempty,min=1,max=100"`
+	Mode   string `form:"mode" validate:"omitempty,oneof=chronological ranked"`
+}
+
+// TimelineEvent es un tweet nuevo del timeline enviado por GET /stream. ID es
+// el cursor del tweet, con el que el cliente puede reanudar la conexión.
+type TimelineEvent struct {
+	ID    string
+	Tweet *Timeline
+}
+
+type TimelinePage struct {
+	Tweets []*Timeline `json:"tweets"`
+	// Before permite pedir tweets más antiguos; vacío si no hay más
+	Before string `json:"before,omitempty"`
+	// After permite consultar si llegaron tweets más recientes
+	After string `json:"after,omitempty"`
+	// Strategy es la estrategia de ranking asignada al lector en modo ranked
+	Strategy string `json:"strategy,omitempty"`
 }
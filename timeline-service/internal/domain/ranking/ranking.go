@@ -0,0 +1,117 @@
+// Package ranking puntúa los tweets candidatos del timeline en modo ranked.
+// Cada fórmula es una Strategy; los usuarios se reparten entre las
+// estrategias configuradas para compararlas en experimentos A/B.
+package ranking
+
+import (
+	"hash/fnv"
+	"math"
+	"time"
+)
+
+// Candidate reúne las señales disponibles de un tweet del timeline.
+type Candidate struct {
+	Likes    int
+	Shares   int
+	Comments int
+	// Age es el tiempo transcurrido desde la publicación del tweet
+	Age time.Duration
+	// Affinity es el peso acumulado de las interacciones del lector con el autor
+	Affinity float64
+}
+
+type Strategy interface {
+	Name() string
+	// Score devuelve la relevancia del candidato; mayor es más relevante
+	Score(candidate Candidate) float64
+}
+
+// Weighted combina el decaimiento por antigüedad con el engagement del tweet
+// y la afinidad con el autor, ambos en escala logarítmica para que los
+// tweets virales no acaparen el timeline.
+type Weighted struct {
+	name string
+	// HalfLife es la antigüedad a la que la puntuación se reduce a la mitad
+	HalfLife time.Duration
+	Likes    float64
+	Shares   float64
+	Comments float64
+	Affinity float64
+}
+
+func (w *Weighted) Name() string {
+	return w.name
+}
+
+func (w *Weighted) Score(candidate Candidate) float64 {
+	engagement := w.Likes*float64(candidate.Likes) +
+		w.Shares*float64(candidate.Shares) +
+		w.Comments*float64(candidate.Comments)
+	affinity := math.Max(candidate.Affinity, 0)
+
+	return decay(candidate.Age, w.HalfLife) *
+		(1 + math.Log1p(math.Max(engagement, 0))) *
+		(1 + w.Affinity*math.Log1p(affinity))
+}
+
+// Recency ordena solo por antigüedad. Sirve como grupo de control, ya que
+// equivale al orden cronológico.
+type Recency struct{}
+
+func (Recency) Name() string {
+	return "recency"
+}
+
+func (Recency) Score(candidate Candidate) float64 {
+	return -candidate.Age.Seconds()
+}
+
+func decay(age, halfLife time.Duration) float64 {
+	if halfLife <= 0 || age <= 0 {
+		return 1
+	}
+	return math.Exp2(-age.Hours() / halfLife.Hours())
+}
+
+// strategies son las estrategias disponibles por nombre.
+var strategies = map[string]Strategy{
+	"balanced": &Weighted{
+		name:     "balanced",
+		HalfLife: 6 * time.Hour,
+		Likes:    1,
+		Shares:   2,
+		Comments: 1.5,
+		Affinity: 0.5,
+	},
+	"affinity": &Weighted{
+		name:     "affinity",
+		HalfLife: 12 * time.Hour,
+		Likes:    1,
+		Shares:   2,
+		Comments: 1.5,
+		Affinity: 1.5,
+	},
+	"recency": Recency{},
+}
+
+// Default es la estrategia que se usa si no se configura ninguna.
+const Default = "balanced"
+
+// Lookup devuelve la estrategia registrada con el nombre indicado.
+func Lookup(name string) (Strategy, bool) {
+	strategy, ok := strategies[name]
+	return strategy, ok
+}
+
+// Assign elige para el usuario una de las estrategias. La asignación depende
+// solo del ID, por lo que cada usuario ve siempre la misma variante mientras
+// no cambie la lista.
+func Assign(userID string, candidates []Strategy) Strategy {
+	if len(candidates) == 0 {
+		return strategies[Default]
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(userID))
+	return candidates[h.Sum32()%uint32(len(candidates))]
+}
@@ -0,0 +1,79 @@
+package ranking
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeighted_Score(t *testing.T) {
+	strategy, ok := Lookup("balanced")
+	assert.True(t, ok)
+
+	base := Candidate{Age: time.Hour}
+
+	// Más engagement o más afinidad aumentan la relevancia
+	liked := base
+	liked.Likes = 10
+	assert.Greater(t, strategy.Score(liked), strategy.Score(base))
+
+	familiar := base
+	familiar.Affinity = 5
+	assert.Greater(t, strategy.Score(familiar), strategy.Score(base))
+
+	// Un compartido pesa más que un like
+	shared := base
+	shared.Shares = 1
+	liked.Likes = 1
+	assert.Greater(t, strategy.Score(shared), strategy.Score(liked))
+
+	// La puntuación se reduce a la mitad en cada semivida
+	weighted := strategy.(*Weighted)
+	old := base
+	old.Age = base.Age + weighted.HalfLife
+	assert.InDelta(t, strategy.Score(base)/2, strategy.Score(old), 1e-9)
+
+	// Las señales negativas no restan relevancia
+	negative := base
+	negative.Affinity = -3
+	assert.Equal(t, strategy.Score(base), strategy.Score(negative))
+}
+
+func TestRecency_Score(t *testing.T) {
+	strategy, ok := Lookup("recency")
+	assert.True(t, ok)
+
+	recent := Candidate{Age: time.Minute, Likes: 0}
+	popular := Candidate{Age: time.Hour, Likes: 1000}
+	assert.Greater(t, strategy.Score(recent), strategy.Score(popular))
+}
+
+func TestLookup(t *testing.T) {
+	strategy, ok := Lookup(Default)
+	assert.True(t, ok)
+	assert.Equal(t, Default, strategy.Name())
+
+	_, ok = Lookup("inexistente")
+	assert.False(t, ok)
+}
+
+func TestAssign(t *testing.T) {
+	balanced, _ := Lookup("balanced")
+	affinity, _ := Lookup("affinity")
+	strategies := []Strategy{balanced, affinity}
+
+	// Sin estrategias configuradas se usa la predeterminada
+	assert.Equal(t, Default, Assign("user", nil).Name())
+
+	// Cada usuario recibe siempre la misma variante
+	assert.Equal(t, Assign("user", strategies), Assign("user", strategies))
+
+	// Los usuarios se reparten entre todas las variantes
+	assigned := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		assigned[Assign(fmt.Sprintf("user-%d", i), strategies).Name()]++
+	}
+	assert.Len(t, assigned, 2)
+}
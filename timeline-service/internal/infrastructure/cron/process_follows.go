@@ -0,0 +1,173 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"timeline-service/internal/infrastructure/keys"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// followStream es el stream donde el user-service publica los cambios de seguimiento
+	followStream  = "follow_stream"
+	followEvent   = "follow"
+	unfollowEvent = "unfollow"
+)
+
+// ProcessFollows consume los eventos de seguimiento para completar el timeline
+// del seguidor con los tweets recientes del usuario seguido, o purgarlos al
+// dejar de seguirlo. Los eventos se procesan en orden para cada réplica.
+func (c *cron) ProcessFollows() {
+	ctx := context.Background()
+
+	if err := c.createGroup(ctx, followStream); err != nil {
+		log.Fatalf("Error al crear el grupo de consumidores: %v", err)
+	}
+
+	lastClaim := time.Now()
+	for {
+		// Reintentar periódicamente los eventos que quedaron sin confirmar
+		if time.Since(lastClaim) >= c.config.ClaimMinIdle {
+			c.claimFollows(ctx)
+			lastClaim = time.Now()
+		}
+
+		streams, err := c.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    consumerGroup,
+			Consumer: c.config.Consumer,
+			Streams:  []string{followStream, ">"},
+			Count:    10,
+			Block:    2 * time.Second,
+		}).Result()
+		if err != nil {
+			if err != redis.Nil {
+				log.Printf("Error al leer del stream de seguimientos: %v", err)
+				time.Sleep(1 * time.Second)
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, entry := range stream.Messages {
+				c.handleFollow(ctx, entry)
+			}
+		}
+	}
+}
+
+func (c *cron) claimFollows(ctx context.Context) {
+	start := "0-0"
+	for {
+		claimed, next, err := c.redis.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   followStream,
+			Group:    consumerGroup,
+			Consumer: c.config.Consumer,
+			MinIdle:  c.config.ClaimMinIdle,
+			Start:    start,
+			Count:    100,
+		}).Result()
+		if err != nil {
+			log.Printf("Error al reclamar eventos de seguimiento pendientes: %v", err)
+			return
+		}
+
+		for _, entry := range claimed {
+			c.handleFollow(ctx, entry)
+		}
+
+		if next == "0-0" {
+			return
+		}
+		start = next
+	}
+}
+
+func (c *cron) handleFollow(ctx context.Context, entry redis.XMessage) {
+	event, _ := entry.Values["type"].(string)
+	userID, _ := entry.Values["user_id"].(string)
+	followerID, _ := entry.Values["follower_id"].(string)
+
+	var err error
+	switch event {
+	case followEvent:
+		err = c.backfillTimeline(ctx, followerID, userID)
+	case unfollowEvent:
+		err = c.purgeTimeline(ctx, followerID, userID)
+	default:
+		log.Printf("Evento de seguimiento desconocido %q en la entrada %s, se descarta", event, entry.ID)
+	}
+
+	if err != nil {
+		// El evento queda pendiente y se reclamará más adelante
+		log.Printf("Error al procesar el evento %s de %s sobre %s: %v", event, followerID, userID, err)
+		return
+	}
+
+	if err := c.redis.XAck(ctx, followStream, consumerGroup, entry.ID).Err(); err != nil {
+		log.Printf("Error al confirmar el evento %s: %v", entry.ID, err)
+	}
+}
+
+// backfillTimeline incorpora al timeline del seguidor los tweets más recientes
+// del usuario seguido.
+func (c *cron) backfillTimeline(ctx context.Context, followerID, userID string) error {
+	if c.backfill <= 0 {
+		return nil
+	}
+
+	// Los tweets de autores con muchos seguidores ya se mezclan al leer
+	celebrity, err := c.redis.SIsMember(ctx, keys.Celebrities, userID).Result()
+	if err != nil {
+		return fmt.Errorf("error al consultar el autor: %w", err)
+	}
+	if celebrity {
+		return nil
+	}
+
+	recent, err := c.redis.ZRevRangeWithScores(ctx, fmt.Sprintf("user_tweets:%s", userID), 0, int64(c.backfill-1)).Result()
+	if err != nil {
+		return fmt.Errorf("error al obtener los tweets del usuario: %w", err)
+	}
+	if len(recent) == 0 {
+		return nil
+	}
+
+	// Ambos sorted sets puntúan por fecha de creación, por lo que basta con
+	// añadir las entradas para que queden en su posición
+	timelineKey := fmt.Sprintf("timeline:%s", followerID)
+
+	pipe := c.redis.TxPipeline()
+	pipe.ZAdd(ctx, timelineKey, recent...)
+	c.trimTimeline(ctx, pipe, timelineKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("error al actualizar el timeline: %w", err)
+	}
+
+	return nil
+}
+
+// purgeTimeline elimina del timeline del seguidor los tweets del usuario que
+// dejó de seguir.
+func (c *cron) purgeTimeline(ctx context.Context, followerID, userID string) error {
+	tweetIDs, err := c.redis.ZRange(ctx, fmt.Sprintf("user_tweets:%s", userID), 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("error al obtener los tweets del usuario: %w", err)
+	}
+	if len(tweetIDs) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, len(tweetIDs))
+	for i, tweetID := range tweetIDs {
+		members[i] = tweetID
+	}
+
+	if err := c.redis.ZRem(ctx, fmt.Sprintf("timeline:%s", followerID), members...).Err(); err != nil {
+		return fmt.Errorf("error al actualizar el timeline: %w", err)
+	}
+
+	return nil
+}
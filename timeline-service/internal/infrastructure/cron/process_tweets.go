@@ -3,97 +3,379 @@ package cron
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
+	"timeline-service/config"
 	"timeline-service/internal/domain/models"
+	"timeline-service/internal/infrastructure/keys"
+	"timeline-service/internal/infrastructure/repository"
 	"timeline-service/internal/interfaces"
 
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	// consumerGroup agrupa a todas las réplicas del timeline-service
+	consumerGroup = "timeline-service"
+	// legacyQueue es la lista usada antes de migrar a streams
+	legacyQueue = "tweet_queue"
+	// legacyProcessed es el set sin caducidad que marcaba los tweets procesados
+	// antes de usar una clave processed_tweets:<id> por tweet
+	legacyProcessed = "processed_tweets"
+	// deleteEvent marca en el stream de tweets un tweet eliminado
+	deleteEvent = "delete"
+	// demoteBatch es el número de timelines que se completan por pipeline al
+	// volver a distribuir al escribir los tweets de un autor
+	demoteBatch = 500
+)
+
+// errTweetDeleted indica que el tweet se eliminó antes de distribuirse, según
+// la marca deleted_tweets:<id> que deja el tweets-service al eliminarlo.
+var errTweetDeleted = errors.New("el tweet ya no existe")
+
 type cron struct {
-	redis *redis.Client
+	redis           *redis.Client
+	repo            interfaces.Repository
+	config          config.StreamConfig
+	retry           config.RetryConfig
+	backfill        int
+	fanoutThreshold int
+	timelineLength  int
+}
+
+// message es una entrada del stream pendiente de distribuir.
+type message struct {
+	streamID string
+	event    string
+	tweetID  string
+	userID   string
 }
 
-func NewCron(redis *redis.Client) interfaces.Cron {
-	return &cron{redis: redis}
+func NewCron(redis *redis.Client, cfg *config.Config, repo interfaces.Repository) interfaces.Cron {
+	return &cron{
+		redis:           redis,
+		repo:            repo,
+		config:          cfg.Stream,
+		retry:           cfg.Retry,
+		backfill:        cfg.Backfill,
+		fanoutThreshold: cfg.FanoutThreshold,
+		timelineLength:  cfg.TimelineLength,
+	}
 }
 
 func (c *cron) ProcessTweets() {
 	ctx := context.Background()
-	numWorkers := 5 // Número de trabajadores
 
-	// Canal para comunicar los IDs de tweets
-	tweetIDs := make(chan string)
+	if err := c.createGroup(ctx, keys.TweetStream); err != nil {
+		log.Fatalf("Error al crear el grupo de consumidores: %v", err)
+	}
+
+	c.migrateQueue(ctx)
+	// Los timelines se migran antes de distribuir tweets nuevos en ellos
+	c.repo.MigrateTimelines(ctx)
+
+	// Las marcas del set anterior ya no se consultan y solo ocupan memoria
+	if err := c.redis.Unlink(ctx, legacyProcessed).Err(); err != nil {
+		log.Printf("Error al eliminar el set %s: %v", legacyProcessed, err)
+	}
+
+	// Canal para comunicar los mensajes del stream
+	messages := make(chan message)
 
 	// Iniciar trabajadores
-	for i := 0; i < numWorkers; i++ {
-		go c.worker(ctx, tweetIDs)
-	}
-
-	luaScript := redis.NewScript(`
-local tweet_id = redis.call('RPOP', KEYS[1])
-if tweet_id then
-    local exists = redis.call('SISMEMBER', KEYS[2], tweet_id)
-    if exists == 0 then
-        redis.call('SADD', KEYS[2], tweet_id)
-        return tweet_id
-    end
-end
-return nil
-    `)
+	for i := 0; i < c.config.Workers; i++ {
+		go c.worker(ctx, messages)
+	}
+
+	go c.claimPending(ctx, messages)
+	go c.trimStream(ctx)
 
 	for {
-		result, err := luaScript.Run(ctx, c.redis, []string{"tweet_queue", "processed_tweets"}).Result()
-		if err != nil && err != redis.Nil {
-			log.Printf("Error al ejecutar el script de Lua: %v", err)
-			time.Sleep(1 * time.Second)
+		streams, err := c.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    consumerGroup,
+			Consumer: c.config.Consumer,
+			Streams:  []string{keys.TweetStream, ">"},
+			Count:    int64(c.config.Workers),
+			Block:    2 * time.Second,
+		}).Result()
+		if err != nil {
+			if err != redis.Nil {
+				log.Printf("Error al leer del stream: %v", err)
+				time.Sleep(1 * time.Second)
+			}
 			continue
 		}
 
-		if result == nil {
-			time.Sleep(2 * time.Second)
-			continue
+		for _, stream := range streams {
+			c.dispatch(stream.Messages, messages)
 		}
+	}
+}
 
-		tweetID, ok := result.(string)
+// createGroup crea el stream y el grupo de consumidores si aún no existen.
+func (c *cron) createGroup(ctx context.Context, stream string) error {
+	err := c.redis.XGroupCreateMkStream(ctx, stream, consumerGroup, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// migrateQueue traslada al stream los tweets que quedaron en la lista usada
+// antes de la migración, para que no se pierdan.
+func (c *cron) migrateQueue(ctx context.Context) {
+	for {
+		tweetID, err := c.redis.RPop(ctx, legacyQueue).Result()
+		if err != nil {
+			if err != redis.Nil {
+				log.Printf("Error al migrar la cola %s: %v", legacyQueue, err)
+			}
+			return
+		}
+
+		if err := c.redis.XAdd(ctx, &redis.XAddArgs{
+			Stream: keys.TweetStream,
+			Values: map[string]interface{}{"tweet_id": tweetID},
+		}).Err(); err != nil {
+			log.Printf("Error al migrar el tweet ID=%s al stream: %v", tweetID, err)
+			c.redis.RPush(ctx, legacyQueue, tweetID)
+			return
+		}
+	}
+}
+
+// claimPending reclama periódicamente las entradas que otro consumidor leyó
+// pero no confirmó, por ejemplo porque la réplica se detuvo a mitad del proceso.
+func (c *cron) claimPending(ctx context.Context, messages chan<- message) {
+	ticker := time.NewTicker(c.config.ClaimMinIdle)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		start := "0-0"
+		for {
+			claimed, next, err := c.redis.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+				Stream:   keys.TweetStream,
+				Group:    consumerGroup,
+				Consumer: c.config.Consumer,
+				MinIdle:  c.config.ClaimMinIdle,
+				Start:    start,
+				Count:    100,
+			}).Result()
+			if err != nil {
+				log.Printf("Error al reclamar entradas pendientes: %v", err)
+				break
+			}
+
+			c.dispatch(claimed, messages)
+
+			if next == "0-0" {
+				break
+			}
+			start = next
+		}
+	}
+}
+
+// trimStream descarta periódicamente las entradas más antiguas que el
+// periodo de retención configurado.
+func (c *cron) trimStream(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		minID := fmt.Sprintf("%d-0", time.Now().Add(-c.config.Retention).UnixMilli())
+		if err := c.redis.XTrimMinIDApprox(ctx, keys.TweetStream, minID, 0).Err(); err != nil {
+			log.Printf("Error al recortar el stream: %v", err)
+		}
+	}
+}
+
+func (c *cron) dispatch(entries []redis.XMessage, messages chan<- message) {
+	for _, entry := range entries {
+		tweetID, ok := entry.Values["tweet_id"].(string)
 		if !ok {
-			log.Printf("Tipo de dato inesperado: %T", result)
+			log.Printf("Entrada %s sin tweet_id, se descarta", entry.ID)
+			c.redis.XAck(context.Background(), keys.TweetStream, consumerGroup, entry.ID)
+			continue
+		}
+
+		event, _ := entry.Values["type"].(string)
+		userID, _ := entry.Values["user_id"].(string)
+
+		// Enviar el mensaje al canal para que lo procesen los trabajadores
+		messages <- message{streamID: entry.ID, event: event, tweetID: tweetID, userID: userID}
+	}
+}
+
+func (c *cron) worker(ctx context.Context, messages <-chan message) {
+	for msg := range messages {
+		if msg.event == deleteEvent {
+			// Un borrado fallido queda pendiente y se reclamará más adelante
+			if err := c.processDelete(ctx, msg.tweetID, msg.userID); err != nil {
+				log.Printf("Error al procesar el borrado del tweet ID=%s: %v", msg.tweetID, err)
+				continue
+			}
+			if err := c.redis.XAck(ctx, keys.TweetStream, consumerGroup, msg.streamID).Err(); err != nil {
+				log.Printf("Error al confirmar el borrado del tweet ID=%s: %v", msg.tweetID, err)
+			}
 			continue
 		}
 
-		// Enviar el tweetID al canal para que lo procesen los trabajadores
-		tweetIDs <- tweetID
+		attempts, err := c.processWithRetry(msg.tweetID)
+		if err != nil {
+			log.Printf("Error al procesar el tweet ID=%s tras %d intentos: %v", msg.tweetID, attempts, err)
+
+			// Si no se puede registrar el fallo la entrada queda pendiente
+			// y se reclamará más adelante
+			if err := c.deadLetter(ctx, msg.tweetID, attempts, err); err != nil {
+				log.Printf("Error al enviar el tweet ID=%s a la cola de fallidos: %v", msg.tweetID, err)
+				continue
+			}
+		}
+
+		if err := c.redis.XAck(ctx, keys.TweetStream, consumerGroup, msg.streamID).Err(); err != nil {
+			log.Printf("Error al confirmar el tweet ID=%s: %v", msg.tweetID, err)
+		}
 	}
 }
 
-func (c *cron) worker(_ context.Context, tweetIDs <-chan string) {
-	for tweetID := range tweetIDs {
-		if err := c.processTweet(tweetID); err != nil {
-			log.Printf("Error al procesar el tweet ID=%s: %v", tweetID, err)
-			// Manejar el error según sea necesario
+// processWithRetry reintenta la distribución con espera exponencial hasta
+// agotar los intentos configurados. La entrada sigue pendiente en el stream
+// mientras tanto; la configuración exige que stream.claim_min_idle supere la
+// espera total (RetryConfig.TotalDelay) para que no la reclame otra réplica.
+func (c *cron) processWithRetry(tweetID string) (int, error) {
+	var err error
+	delay := c.retry.BaseDelay
+
+	for attempt := 1; ; attempt++ {
+		if err = c.processTweet(tweetID); err == nil {
+			return attempt, nil
+		}
+		if attempt >= c.retry.MaxAttempts {
+			return attempt, err
 		}
+
+		log.Printf("Reintentando el tweet ID=%s en %s (intento %d): %v", tweetID, delay, attempt, err)
+		time.Sleep(delay)
+
+		delay = c.nextDelay(delay)
 	}
 }
 
+// nextDelay duplica la espera entre reintentos sin superar retry.max_delay.
+func (c *cron) nextDelay(delay time.Duration) time.Duration {
+	delay *= 2
+	if delay > c.retry.MaxDelay {
+		return c.retry.MaxDelay
+	}
+	return delay
+}
+
+// deadLetter registra el tweet en la cola de fallidos para que un administrador
+// pueda revisarlo, reintentarlo o descartarlo.
+func (c *cron) deadLetter(ctx context.Context, tweetID string, attempts int, cause error) error {
+	failedAt := time.Now()
+
+	data, err := json.Marshal(models.DeadLetter{
+		TweetID:  tweetID,
+		Error:    cause.Error(),
+		Attempts: attempts,
+		FailedAt: failedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("error al serializar el tweet fallido: %w", err)
+	}
+
+	pipe := c.redis.TxPipeline()
+	pipe.Set(ctx, keys.DeadLetter(tweetID), data, 0)
+	pipe.ZAdd(ctx, keys.DeadLetters, redis.Z{Score: float64(failedAt.UnixMilli()), Member: tweetID})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (c *cron) processTweet(tweetID string) error {
 	ctx := context.Background()
+
+	// Una entrada reclamada puede haberse distribuido ya antes de confirmarse
+	processedKey := fmt.Sprintf("processed_tweets:%s", tweetID)
+	processed, err := c.redis.Exists(ctx, processedKey).Result()
+	if err != nil {
+		return fmt.Errorf("error al verificar el tweet procesado: %w", err)
+	}
+	if processed > 0 {
+		return nil
+	}
+
 	tweet, err := c.getTweet(ctx, tweetID)
+	if errors.Is(err, errTweetDeleted) {
+		// El tweet se eliminó antes de distribuirse; no hay nada que hacer
+		log.Printf("Tweet ID=%s eliminado antes de distribuirse", tweetID)
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("error al obtener el tweet: %w", err)
 	}
 
+	// Los autores con demasiados seguidores no se distribuyen al escribir; el
+	// timeline-service mezcla sus tweets al leer cada timeline
+	followersCount, err := c.redis.SCard(ctx, fmt.Sprintf("followers:%s", tweet.UserID)).Result()
+	if err != nil {
+		return fmt.Errorf("error al contar los seguidores: %w", err)
+	}
+
+	// La puntuación es la fecha de creación para ordenar y paginar con cursores
+	createdAt := tweet.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	entry := redis.Z{Score: float64(createdAt.UnixMilli()), Member: tweetID}
+
+	// El anuncio lleva la puntuación y el ID, que forman el cursor del tweet
+	update := fmt.Sprintf("%d:%s", int64(entry.Score), tweetID)
+
+	if c.fanoutThreshold > 0 && followersCount > int64(c.fanoutThreshold) {
+		pipe := c.redis.TxPipeline()
+		pipe.SAdd(ctx, keys.Celebrities, tweet.UserID)
+		pipe.ZAddNX(ctx, keys.CelebritiesSince, redis.Z{Score: entry.Score, Member: tweet.UserID})
+		// Un solo anuncio por autor en lugar de uno por seguidor; GET /stream
+		// se suscribe a los autores que sigue el lector
+		pipe.Publish(ctx, keys.AuthorChannel(tweet.UserID), update)
+		pipe.Set(ctx, processedKey, 1, c.config.Retention)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("error al registrar el autor con muchos seguidores: %w", err)
+		}
+
+		return nil
+	}
+
+	// followers:<id> solo contiene seguidores aprobados: las solicitudes
+	// pendientes hacia cuentas privadas viven aparte en el user-service, así que
+	// sus tweets nunca llegan a quien aún no fue aceptado
 	followers, err := c.getFollowers(ctx, tweet.UserID)
 	if err != nil {
 		return fmt.Errorf("error al obtener los seguidores: %w", err)
 	}
 
-	pipe := c.redis.Pipeline()
+	if err := c.demoteCelebrity(ctx, tweet.UserID, followers); err != nil {
+		return err
+	}
+
+	pipe := c.redis.TxPipeline()
 	for _, followerID := range followers {
 		timelineKey := fmt.Sprintf("timeline:%s", followerID)
-		pipe.LPush(ctx, timelineKey, tweetID)
+		pipe.ZAdd(ctx, timelineKey, entry)
+		c.trimTimeline(ctx, pipe, timelineKey)
+		pipe.Publish(ctx, keys.TimelineChannel(followerID), update)
 	}
+	// La marca caduca junto con la retención del stream para no crecer sin límite
+	pipe.Set(ctx, processedKey, 1, c.config.Retention)
 
 	_, err = pipe.Exec(ctx)
 	if err != nil {
@@ -104,16 +386,111 @@ func (c *cron) processTweet(tweetID string) error {
 	return nil
 }
 
+// demoteCelebrity vuelve a distribuir al escribir los tweets de un autor que
+// bajó del umbral de seguidores. Antes de dejar de mezclarlos al leer, copia a
+// los timelines de sus seguidores los tweets que publicó mientras se mezclaban,
+// hasta la longitud máxima del timeline, para que no desaparezcan.
+func (c *cron) demoteCelebrity(ctx context.Context, authorID string, followers []string) error {
+	pipe := c.redis.Pipeline()
+	member := pipe.SIsMember(ctx, keys.Celebrities, authorID)
+	since := pipe.ZScore(ctx, keys.CelebritiesSince, authorID)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return fmt.Errorf("error al consultar el autor con muchos seguidores: %w", err)
+	}
+	if !member.Val() {
+		return nil
+	}
+
+	// Los autores registrados antes de guardar la fecha se completan con sus
+	// tweets más recientes
+	from := "-inf"
+	if since.Err() == nil {
+		from = strconv.FormatInt(int64(since.Val()), 10)
+	}
+
+	entries, err := c.redis.ZRevRangeByScoreWithScores(ctx, fmt.Sprintf("user_tweets:%s", authorID), &redis.ZRangeBy{
+		Min:   from,
+		Max:   "+inf",
+		Count: int64(c.timelineLength),
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("error al obtener los tweets del autor: %w", err)
+	}
+
+	if len(entries) > 0 {
+		for start := 0; start < len(followers); start += demoteBatch {
+			end := min(start+demoteBatch, len(followers))
+
+			pipe := c.redis.Pipeline()
+			for _, followerID := range followers[start:end] {
+				timelineKey := fmt.Sprintf("timeline:%s", followerID)
+				pipe.ZAdd(ctx, timelineKey, entries...)
+				c.trimTimeline(ctx, pipe, timelineKey)
+			}
+			if _, err := pipe.Exec(ctx); err != nil {
+				return fmt.Errorf("error al completar los timelines: %w", err)
+			}
+		}
+	}
+
+	pipe = c.redis.TxPipeline()
+	pipe.SRem(ctx, keys.Celebrities, authorID)
+	pipe.ZRem(ctx, keys.CelebritiesSince, authorID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("error al retirar el autor con muchos seguidores: %w", err)
+	}
+
+	return nil
+}
+
+// processDelete retira un tweet eliminado de los timelines de los seguidores
+// de su autor.
+func (c *cron) processDelete(ctx context.Context, tweetID, userID string) error {
+	followers, err := c.getFollowers(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	pipe := c.redis.TxPipeline()
+	for _, followerID := range followers {
+		pipe.ZRem(ctx, fmt.Sprintf("timeline:%s", followerID), tweetID)
+	}
+	// Evita que una entrada de creación aún pendiente vuelva a distribuirlo
+	pipe.Set(ctx, fmt.Sprintf("processed_tweets:%s", tweetID), 1, c.config.Retention)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("error al actualizar los timelines: %w", err)
+	}
+
+	fmt.Printf("Eliminado tweet: ID=%s\n", tweetID)
+	return nil
+}
+
+// trimTimeline descarta los tweets más antiguos que exceden la longitud
+// máxima del timeline.
+func (c *cron) trimTimeline(ctx context.Context, pipe redis.Pipeliner, timelineKey string) {
+	repository.TrimTimeline(ctx, pipe, timelineKey, c.timelineLength)
+}
+
 func (r *cron) getTweet(ctx context.Context, tweetID string) (*models.Tweet, error) {
 	// Construye la clave del tweet
 	tweetKey := fmt.Sprintf("tweets:%s", tweetID)
 
 	// Obtén el valor de Redis
 	tweetData, err := r.redis.Get(ctx, tweetKey).Result()
-	if err != nil {
-		if err == redis.Nil {
-			return nil, fmt.Errorf("el tweet con ID %s no existe", tweetID)
+	if err == redis.Nil {
+		// Solo un tweet con marca de borrado se da por eliminado; si no, el
+		// tweet aún no es legible y se reintenta
+		deleted, err := r.redis.Exists(ctx, fmt.Sprintf("deleted_tweets:%s", tweetID)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("error al verificar el tweet eliminado: %w", err)
 		}
+		if deleted > 0 {
+			return nil, errTweetDeleted
+		}
+		return nil, fmt.Errorf("el tweet %s no está disponible", tweetID)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("error al obtener el tweet: %w", err)
 	}
 
@@ -0,0 +1,201 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+	"timeline-service/config"
+	"timeline-service/internal/domain/models"
+	"timeline-service/internal/infrastructure/keys"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCron(t *testing.T) (*cron, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+
+	return &cron{
+		redis:  redis.NewClient(&redis.Options{Addr: server.Addr()}),
+		config: config.StreamConfig{Workers: 1, ClaimMinIdle: time.Minute, Retention: time.Hour},
+		retry: config.RetryConfig{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    2 * time.Millisecond,
+		},
+		fanoutThreshold: 10,
+		timelineLength:  5,
+	}, server
+}
+
+func TestCron_NextDelay(t *testing.T) {
+	c := &cron{retry: config.RetryConfig{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}}
+
+	var delays []time.Duration
+	for delay := c.retry.BaseDelay; len(delays) < 6; delay = c.nextDelay(delay) {
+		delays = append(delays, delay)
+	}
+
+	assert.Equal(t, []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}, delays)
+}
+
+func TestCron_ProcessWithRetry(t *testing.T) {
+	c, server := newTestCron(t)
+	ctx := context.Background()
+
+	// Un payload corrupto falla en todos los intentos
+	server.Set("tweets:roto", "{")
+
+	attempts, err := c.processWithRetry("roto")
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts)
+
+	// El fallo queda registrado en la cola de fallidos con sus intentos
+	assert.NoError(t, c.deadLetter(ctx, "roto", attempts, err))
+
+	ids, err := c.redis.ZRange(ctx, keys.DeadLetters, 0, -1).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"roto"}, ids)
+
+	data, err := c.redis.Get(ctx, keys.DeadLetter("roto")).Bytes()
+	assert.NoError(t, err)
+	var letter models.DeadLetter
+	assert.NoError(t, json.Unmarshal(data, &letter))
+	assert.Equal(t, "roto", letter.TweetID)
+	assert.Equal(t, 3, letter.Attempts)
+	assert.NotEmpty(t, letter.Error)
+}
+
+func TestCron_ProcessWithRetry_Success(t *testing.T) {
+	c, server := newTestCron(t)
+	ctx := context.Background()
+
+	server.Set("tweets:ok", `{"userId":"author","content":"Tweet","createdAt":"2024-05-10T12:00:00Z"}`)
+	server.SAdd("followers:author", "reader")
+
+	attempts, err := c.processWithRetry("ok")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+
+	score, err := c.redis.ZScore(ctx, "timeline:reader", "ok").Result()
+	assert.NoError(t, err)
+	assert.Equal(t, float64(time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC).UnixMilli()), score)
+
+	// Una entrada reclamada tras distribuirse no se vuelve a procesar
+	assert.NoError(t, c.redis.ZRem(ctx, "timeline:reader", "ok").Err())
+	_, err = c.processWithRetry("ok")
+	assert.NoError(t, err)
+	exists, err := c.redis.Exists(ctx, "timeline:reader").Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), exists)
+}
+
+func TestCron_ProcessTweet_Celebrity(t *testing.T) {
+	c, server := newTestCron(t)
+	c.fanoutThreshold = 1
+	ctx := context.Background()
+
+	base := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	addTweet := func(id string, offset int) {
+		createdAt := base.Add(time.Duration(offset) * time.Minute)
+		server.Set("tweets:"+id, `{"userId":"author","content":"Tweet","createdAt":"`+createdAt.Format(time.RFC3339)+`"}`)
+		server.ZAdd("user_tweets:author", float64(createdAt.UnixMilli()), id)
+	}
+
+	// El tweet anterior ya se distribuyó cuando el autor tenía pocos seguidores
+	addTweet("antiguo", 0)
+	server.SAdd("followers:author", "reader", "other")
+
+	// Por encima del umbral los tweets no se distribuyen; se anuncian una vez
+	// en el canal del autor
+	pubsub := c.redis.Subscribe(ctx, keys.AuthorChannel("author"))
+	defer pubsub.Close()
+	_, err := pubsub.Receive(ctx)
+	assert.NoError(t, err)
+
+	addTweet("uno", 1)
+	addTweet("dos", 2)
+	assert.NoError(t, c.processTweet("uno"))
+	assert.NoError(t, c.processTweet("dos"))
+
+	receiveCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	for offset, id := range []string{"uno", "dos"} {
+		msg, err := pubsub.ReceiveMessage(receiveCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("%d:%s", base.Add(time.Duration(offset+1)*time.Minute).UnixMilli(), id), msg.Payload)
+	}
+
+	celebrity, err := c.redis.SIsMember(ctx, keys.Celebrities, "author").Result()
+	assert.NoError(t, err)
+	assert.True(t, celebrity)
+	exists, err := c.redis.Exists(ctx, "timeline:reader").Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), exists)
+
+	// Al bajar del umbral, los tweets publicados mientras se mezclaban al leer
+	// pasan a los timelines antes de dejar de mezclarse
+	server.SRem("followers:author", "other")
+	addTweet("tres", 3)
+	assert.NoError(t, c.processTweet("tres"))
+
+	timeline, err := c.redis.ZRange(ctx, "timeline:reader", 0, -1).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"uno", "dos", "tres"}, timeline)
+
+	celebrity, err = c.redis.SIsMember(ctx, keys.Celebrities, "author").Result()
+	assert.NoError(t, err)
+	assert.False(t, celebrity)
+	_, err = c.redis.ZScore(ctx, keys.CelebritiesSince, "author").Result()
+	assert.ErrorIs(t, err, redis.Nil)
+}
+
+func TestCron_ProcessTweet_CelebrityWithoutCreatedAt(t *testing.T) {
+	c, server := newTestCron(t)
+	c.fanoutThreshold = 1
+	ctx := context.Background()
+
+	// Las entradas antiguas del stream no incluyen createdAt
+	server.Set("tweets:legado", `{"userId":"author","content":"Tweet"}`)
+	server.SAdd("followers:author", "reader", "other")
+
+	before := time.Now()
+	assert.NoError(t, c.processTweet("legado"))
+
+	// El inicio se registra con la misma fecha que el tweet, no en el año 1
+	since, err := c.redis.ZScore(ctx, keys.CelebritiesSince, "author").Result()
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, since, float64(before.UnixMilli()))
+}
+
+func TestCron_ProcessTweet_Missing(t *testing.T) {
+	c, server := newTestCron(t)
+	ctx := context.Background()
+
+	// Un tweet eliminado antes de distribuirse se descarta sin reintentos
+	server.Set("deleted_tweets:borrado", "1")
+	attempts, err := c.processWithRetry("borrado")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+
+	// Un tweet que aún no se puede leer agota los reintentos y pasa a la cola
+	// de fallidos en lugar de perderse
+	attempts, err = c.processWithRetry("pendiente")
+	assert.Error(t, err)
+	assert.Equal(t, c.retry.MaxAttempts, attempts)
+	assert.NoError(t, c.deadLetter(ctx, "pendiente", attempts, err))
+
+	ids, err := c.redis.ZRange(ctx, keys.DeadLetters, 0, -1).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pendiente"}, ids)
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"timeline-service/internal/domain/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (s *HTTPServer) deadLetters(c *gin.Context) {
+	// Valores por defecto para los parámetros no enviados
+	query := models.DeadLetterQuery{Page: 1, Size: 10}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	deadLetters, err := s.service.DeadLetters(c.Request.Context(), query.Page, query.Size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, deadLetters)
+}
+
+func (s *HTTPServer) replayDeadLetter(c *gin.Context) {
+	if err := s.service.ReplayDeadLetter(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tweet reenviado para su distribución"})
+}
+
+func (s *HTTPServer) discardDeadLetter(c *gin.Context) {
+	if err := s.service.DiscardDeadLetter(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tweet descartado correctamente"})
+}
+
+// errorStatus traduce los errores de dominio al código HTTP correspondiente.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrDeadLetterNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidCursor):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrRankingExpired):
+		return http.StatusGone
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1 +1,88 @@
 package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"shared/auth"
+	"testing"
+	"timeline-service/internal/application"
+	"timeline-service/internal/infrastructure/repository"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T) *HTTPServer {
+	gin.SetMode(gin.TestMode)
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	service := application.NewService(repository.NewRepository(client, 800, nil, 200))
+
+	return NewHTTPServer(gin.New(), service, validator.New(), []string{"admin"}, auth.Middleware(nil, true), nil)
+}
+
+func TestHTTPServer_DeadLetters(t *testing.T) {
+	server := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		query  string
+		user   string
+		status int
+	}{
+		{"valores por defecto", "", "admin", http.StatusOK},
+		{"página válida", "?page=2&size=5", "admin", http.StatusOK},
+		{"página cero", "?page=0", "admin", http.StatusBadRequest},
+		{"página negativa", "?page=-1", "admin", http.StatusBadRequest},
+		{"página no numérica", "?page=uno", "admin", http.StatusBadRequest},
+		{"tamaño excesivo", "?size=1000", "admin", http.StatusBadRequest},
+		{"usuario sin permisos", "", "user", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/admin/dead-letters"+tt.query, nil)
+			assert.NoError(t, err)
+			req.Header.Set("User-ID", tt.user)
+
+			w := httptest.NewRecorder()
+			server.engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestHTTPServer_SessionActive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	redisServer := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+	verifier, err := auth.NewVerifier(auth.Config{Algorithm: "HS256", Secret: "secreto"}, client)
+	assert.NoError(t, err)
+	server := &HTTPServer{verifier: verifier}
+	redisServer.HSet("sessions:s1", "user_id", "u1")
+
+	newContext := func(userID, sessionID string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/stream", nil)
+		c.Set(auth.UserIDKey, userID)
+		if sessionID != "" {
+			c.Set(auth.SessionIDKey, sessionID)
+		}
+		return c
+	}
+
+	assert.True(t, server.sessionActive(newContext("u1", "s1")))
+	assert.False(t, server.sessionActive(newContext("u2", "s1")))
+	// Las conexiones con el header User-ID no tienen sesión
+	assert.True(t, server.sessionActive(newContext("u1", "")))
+
+	// Al cerrar la sesión la conexión abierta deja de estar autorizada
+	redisServer.Del("sessions:s1")
+	assert.False(t, server.sessionActive(newContext("u1", "s1")))
+}
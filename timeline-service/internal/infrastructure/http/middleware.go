@@ -2,20 +2,25 @@ package http
 
 import (
 	"net/http"
+	"shared/auth"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// AdminMiddleware restringe el acceso a los usuarios configurados como
+// administradores. Debe usarse después de auth.Middleware.
+func AdminMiddleware(admins []string) gin.HandlerFunc {
+	allowed := make(map[string]struct{}, len(admins))
+	for _, admin := range admins {
+		allowed[admin] = struct{}{}
+	}
+
 	return func(c *gin.Context) {
-		userID := c.GetHeader("User-ID")
-		if userID == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User-ID no proporcionado"})
+		if _, ok := allowed[c.GetString(auth.UserIDKey)]; !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Acceso restringido a administradores"})
 			return
 		}
 
-		// Puedes agregar lógica adicional, como validar el tweetID
-		c.Set("userID", userID)
 		c.Next()
 	}
 }
@@ -1,25 +1,43 @@
 package http
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
-	"strconv"
+	"shared/auth"
+	"time"
+	"timeline-service/internal/domain/models"
 	"timeline-service/internal/interfaces"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
+// heartbeatInterval es la frecuencia de los comentarios que mantienen abierta
+// la conexión de GET /stream a través de proxies con tiempo de inactividad
+const heartbeatInterval = 15 * time.Second
+
 type HTTPServer struct {
 	engine   *gin.Engine
 	validate *validator.Validate
 	service  interfaces.Service
+	admins   []string
+	auth     gin.HandlerFunc
+	verifier *auth.Verifier
 }
 
-func NewHTTPServer(engine *gin.Engine, service interfaces.Service, validate *validator.Validate) *HTTPServer {
+// NewHTTPServer registra las rutas. verifier vuelve a comprobar la sesión de
+// las conexiones de GET /stream abiertas; puede ser nil si no hay tokens.
+func NewHTTPServer(engine *gin.Engine, service interfaces.Service, validate *validator.Validate, admins []string, authMiddleware gin.HandlerFunc, verifier *auth.Verifier) *HTTPServer {
 	server := &HTTPServer{
 		engine:   engine,
 		validate: validate,
 		service:  service,
+		admins:   admins,
+		auth:     authMiddleware,
+		verifier: verifier,
 	}
 	server.registerRoutes()
 	return server
@@ -32,28 +50,120 @@ func (s *HTTPServer) Run(port string) {
 }
 
 func (s *HTTPServer) registerRoutes() {
-	authorized := s.engine.Group("/", AuthMiddleware())
+	authorized := s.engine.Group("/", s.auth)
 	{
 		authorized.GET("/paginate", s.paginate)
+		authorized.GET("/stream", s.stream)
+	}
 
+	admin := s.engine.Group("/admin", s.auth, AdminMiddleware(s.admins))
+	{
+		admin.GET("/dead-letters", s.deadLetters)
+		admin.POST("/dead-letters/:id/replay", s.replayDeadLetter)
+		admin.DELETE("/dead-letters/:id", s.discardDeadLetter)
 	}
 }
 
 func (s *HTTPServer) paginate(c *gin.Context) {
-	id := c.GetString("userID")
-	page := c.DefaultQuery("page", "1")
-	size := c.DefaultQuery("size", "10")
+	var query models.TimelineQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	// Paginar el timeline usando el servicio
+	page, err := s.service.Paginate(c.Request.Context(), c.GetString(auth.UserIDKey), &query)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Responder con el timeline paginado
+	c.JSON(http.StatusOK, page)
+}
 
-	pageInt, _ := strconv.Atoi(page)
-	sizeInt, _ := strconv.Atoi(size)
+// stream envía por Server-Sent Events los tweets nuevos del timeline. Cada
+// evento lleva como id el cursor del tweet; al reconectar, el cliente lo envía
+// en el header Last-Event-ID (o en el parámetro lastEventId) para recibir los
+// tweets que se perdió. La sesión se comprueba de nuevo en cada heartbeat y la
+// conexión se cierra si se revocó.
+func (s *HTTPServer) stream(c *gin.Context) {
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
 
-	// Paginar los usuarios usando el servicio
-	tweets, err := s.service.Paginate(c.Request.Context(), id, pageInt, sizeInt)
+	ctx := c.Request.Context()
+	events, err := s.service.Stream(ctx, c.GetString(auth.UserIDKey), lastEventID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	// Responder con los usuarios paginados
-	c.JSON(http.StatusOK, tweets)
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Evita que nginx acumule los eventos antes de reenviarlos
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if !s.sessionActive(c) {
+				return
+			}
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case event, ok := <-events:
+			if !ok {
+				// El stream terminó por un error; el cliente reconectará
+				return
+			}
+
+			data, err := json.Marshal(event.Tweet)
+			if err != nil {
+				log.Printf("Error al serializar el tweet %s: %v", event.Tweet.ID, err)
+				continue
+			}
+			if _, err := fmt.Fprintf(c.Writer, "id: %s\nevent: tweet\ndata: %s\n\n", event.ID, data); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// sessionActive comprueba que la sesión del token con el que se abrió la
+// conexión no se haya cerrado. Las conexiones con el header User-ID no tienen
+// sesión. Un error de Redis no cierra la conexión.
+func (s *HTTPServer) sessionActive(c *gin.Context) bool {
+	sessionID := c.GetString(auth.SessionIDKey)
+	if sessionID == "" || s.verifier == nil {
+		return true
+	}
+
+	err := s.verifier.CheckSession(c.Request.Context(), sessionID, c.GetString(auth.UserIDKey))
+	if errors.Is(err, auth.ErrInvalidToken) {
+		return false
+	}
+	if err != nil {
+		log.Printf("Error al comprobar la sesión %s: %v", sessionID, err)
+	}
+
+	return true
 }
@@ -0,0 +1,41 @@
+// Package keys reúne las claves de Redis que comparten el cron y el
+// repositorio del timeline-service, para que ambos lean y escriban las mismas.
+package keys
+
+import "fmt"
+
+const (
+	// TweetStream es el stream donde el tweets-service publica los tweets
+	// nuevos y eliminados
+	TweetStream = "tweet_stream"
+	// DeadLetters indexa por fecha los tweets cuya distribución falló
+	DeadLetters = "dead_letters"
+	// Celebrities contiene los autores cuyos tweets se mezclan al leer el timeline
+	Celebrities = "celebrities"
+	// CelebritiesSince guarda la fecha del primer tweet que cada autor de
+	// Celebrities dejó de distribuir al escribir
+	CelebritiesSince = "celebrities_since"
+	// TimelineUpdates es el prefijo de los canales pub/sub por usuario donde se
+	// anuncian los tweets distribuidos a su timeline, para GET /stream
+	TimelineUpdates = "timeline_updates"
+	// AuthorUpdates es el prefijo de los canales pub/sub donde se anuncian los
+	// tweets de los autores de Celebrities, que no se distribuyen al escribir
+	AuthorUpdates = "author_updates"
+)
+
+// DeadLetter es la clave con el detalle del fallo de distribución del tweet.
+func DeadLetter(tweetID string) string {
+	return fmt.Sprintf("%s:%s", DeadLetters, tweetID)
+}
+
+// TimelineChannel es el canal donde se anuncian los tweets distribuidos al
+// timeline del usuario.
+func TimelineChannel(userID string) string {
+	return fmt.Sprintf("%s:%s", TimelineUpdates, userID)
+}
+
+// AuthorChannel es el canal donde se anuncian los tweets del autor mientras
+// pertenece a Celebrities.
+func AuthorChannel(authorID string) string {
+	return fmt.Sprintf("%s:%s", AuthorUpdates, authorID)
+}
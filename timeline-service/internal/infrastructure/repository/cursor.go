@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"timeline-service/internal/domain/models"
+)
+
+// cursor identifica una posición del timeline por la puntuación del tweet
+// (su fecha de creación en milisegundos) y su ID, que desempata tweets con la
+// misma puntuación.
+type cursor struct {
+	score float64
+	id    string
+}
+
+func (c cursor) encode() string {
+	raw := fmt.Sprintf("%d:%s", int64(c.score), c.id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// less indica si la posición (score, id) es anterior al cursor en el orden
+// del timeline, que va del más reciente al más antiguo.
+func (c cursor) less(score float64, id string) bool {
+	return score < c.score || (score == c.score && id < c.id)
+}
+
+// greater indica si la posición (score, id) es posterior al cursor, es decir,
+// más reciente.
+func (c cursor) greater(score float64, id string) bool {
+	return score > c.score || (score == c.score && id > c.id)
+}
+
+func decodeCursor(value string) (*cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, models.ErrInvalidCursor
+	}
+
+	score, id, found := strings.Cut(string(raw), ":")
+	if !found || id == "" {
+		return nil, models.ErrInvalidCursor
+	}
+
+	n, err := strconv.ParseInt(score, 10, 64)
+	if err != nil {
+		return nil, models.ErrInvalidCursor
+	}
+
+	return &cursor{score: float64(n), id: id}, nil
+}
+
+// rankedCursor identifica una página del timeline ranked por el token de la
+// clasificación guardada en la primera página y la posición dentro de ella.
+type rankedCursor struct {
+	token  string
+	offset int
+}
+
+func (c rankedCursor) encode() string {
+	raw := fmt.Sprintf("%s:%d", c.token, c.offset)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeRankedCursor(value string) (*rankedCursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, models.ErrInvalidCursor
+	}
+
+	token, position, found := strings.Cut(string(raw), ":")
+	if !found || token == "" {
+		return nil, models.ErrInvalidCursor
+	}
+
+	offset, err := strconv.Atoi(position)
+	if err != nil || offset < 0 {
+		return nil, models.ErrInvalidCursor
+	}
+
+	return &rankedCursor{token: token, offset: offset}, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"timeline-service/internal/domain/models"
+	"timeline-service/internal/infrastructure/keys"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func (r *Repository) DeadLetters(ctx context.Context, page, size int) ([]*models.DeadLetter, error) {
+	start := (page - 1) * size
+	end := start + size - 1
+
+	// Los fallos más recientes primero
+	tweetIDs, err := r.redis.ZRevRange(ctx, keys.DeadLetters, int64(start), int64(end)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error al recuperar la cola de fallidos: %w", err)
+	}
+
+	if len(tweetIDs) == 0 {
+		return []*models.DeadLetter{}, nil
+	}
+
+	letterKeys := make([]string, len(tweetIDs))
+	for i, tweetID := range tweetIDs {
+		letterKeys[i] = keys.DeadLetter(tweetID)
+	}
+
+	dataList, err := r.redis.MGet(ctx, letterKeys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error al recuperar los tweets fallidos: %w", err)
+	}
+
+	deadLetterList := make([]*models.DeadLetter, 0, len(dataList))
+	for i, data := range dataList {
+		if data == nil {
+			continue
+		}
+		dataJSON, ok := data.(string)
+		if !ok {
+			return nil, fmt.Errorf("tipo de dato inesperado para tweetID %s", tweetIDs[i])
+		}
+
+		var deadLetter models.DeadLetter
+		if err := json.Unmarshal([]byte(dataJSON), &deadLetter); err != nil {
+			return nil, fmt.Errorf("error al deserializar tweetID %s: %w", tweetIDs[i], err)
+		}
+		deadLetterList = append(deadLetterList, &deadLetter)
+	}
+
+	return deadLetterList, nil
+}
+
+func (r *Repository) ReplayDeadLetter(ctx context.Context, tweetID string) error {
+	if err := r.deadLetterExists(ctx, tweetID); err != nil {
+		return err
+	}
+
+	// Devolver el tweet al stream para que se distribuya de nuevo
+	pipe := r.redis.TxPipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: keys.TweetStream,
+		Values: map[string]interface{}{"tweet_id": tweetID},
+	})
+	pipe.ZRem(ctx, keys.DeadLetters, tweetID)
+	pipe.Del(ctx, keys.DeadLetter(tweetID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("error al reintentar el tweet fallido: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) DiscardDeadLetter(ctx context.Context, tweetID string) error {
+	if err := r.deadLetterExists(ctx, tweetID); err != nil {
+		return err
+	}
+
+	pipe := r.redis.TxPipeline()
+	pipe.ZRem(ctx, keys.DeadLetters, tweetID)
+	pipe.Del(ctx, keys.DeadLetter(tweetID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("error al descartar el tweet fallido: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) deadLetterExists(ctx context.Context, tweetID string) error {
+	_, err := r.redis.ZScore(ctx, keys.DeadLetters, tweetID).Result()
+	if err == redis.Nil {
+		return models.ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error al consultar la cola de fallidos: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MigrateTimelines convierte a sorted sets los timelines guardados como listas
+// antes de paginar con cursores. Un timeline que aún no se ha convertido
+// cuando se lee se convierte en ese momento.
+func (r *Repository) MigrateTimelines(ctx context.Context) {
+	iter := r.redis.ScanType(ctx, 0, "timeline:*", 100, "list").Iterator()
+	for iter.Next(ctx) {
+		if err := r.migrateTimeline(ctx, iter.Val()); err != nil {
+			log.Printf("Error al migrar el timeline %s: %v", iter.Val(), err)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		log.Printf("Error al recorrer los timelines a migrar: %v", err)
+	}
+}
+
+// migrateTimeline convierte un timeline guardado como lista, con el tweet más
+// reciente primero. La puntuación es la fecha de creación de cada tweet; los
+// guardados antes de incluirla en el tweet reciben una unidad menos que el
+// anterior de la lista para conservar su orden. Los tweets que ya no existen
+// se descartan.
+func (r *Repository) migrateTimeline(ctx context.Context, timelineKey string) error {
+	return r.redis.Watch(ctx, func(tx *redis.Tx) error {
+		// Otra réplica o una lectura concurrente puede haberlo migrado ya
+		keyType, err := tx.Type(ctx, timelineKey).Result()
+		if err != nil || keyType != "list" {
+			return err
+		}
+
+		tweetIDs, err := tx.LRange(ctx, timelineKey, 0, -1).Result()
+		if err != nil {
+			return err
+		}
+
+		tweets, err := r.getTweets(ctx, tweetIDs)
+		if err != nil {
+			return err
+		}
+
+		entries := make([]redis.Z, len(tweets))
+		previous := float64(time.Now().UnixMilli())
+		for i, tweet := range tweets {
+			score := previous - 1
+			if !tweet.CreatedAt.IsZero() {
+				score = float64(tweet.CreatedAt.UnixMilli())
+			}
+			entries[i] = redis.Z{Score: score, Member: tweet.ID}
+			previous = score
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, timelineKey)
+			if len(entries) > 0 {
+				pipe.ZAdd(ctx, timelineKey, entries...)
+			}
+			TrimTimeline(ctx, pipe, timelineKey, r.timelineLength)
+			return nil
+		})
+		return err
+	}, timelineKey)
+}
+
+// isWrongType indica si Redis rechazó el comando porque la clave es de otro
+// tipo, como un timeline que aún es una lista.
+func isWrongType(err error) bool {
+	var redisErr redis.Error
+	return errors.As(err, &redisErr) && strings.HasPrefix(redisErr.Error(), "WRONGTYPE")
+}
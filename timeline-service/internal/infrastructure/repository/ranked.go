@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+	"timeline-service/internal/domain/models"
+	"timeline-service/internal/domain/ranking"
+)
+
+// affinityKey es el sorted set autor -> peso de las interacciones del usuario
+// con sus tweets, que mantiene el tweets-service.
+func affinityKey(userID string) string {
+	return fmt.Sprintf("affinity:%s", userID)
+}
+
+// rankedTTL es el tiempo durante el que se conserva el orden de una
+// clasificación para pedir sus páginas siguientes.
+const rankedTTL = 15 * time.Minute
+
+// rankedKey es la lista con los IDs de una clasificación en el orden en que se
+// paginan.
+func rankedKey(userID, token string) string {
+	return fmt.Sprintf("ranked:%s:%s", userID, token)
+}
+
+type rankedTweet struct {
+	tweet *models.Timeline
+	// created es la puntuación del tweet en el timeline, su fecha de creación en milisegundos
+	created float64
+	score   float64
+}
+
+// paginateRanked ordena por relevancia los tweets más recientes del timeline
+// según la estrategia asignada al lector. La primera página guarda el orden
+// completo en ranked:<id>:<token> y las siguientes lo leen por posición, por
+// lo que ni los tweets nuevos ni los cambios de puntuación repiten u omiten
+// tweets entre páginas.
+func (r *Repository) paginateRanked(ctx context.Context, userID string, query *models.TimelineQuery) (*models.TimelinePage, error) {
+	size := query.Size
+	if size <= 0 {
+		size = defaultPageSize
+	}
+
+	c, err := decodeRankedCursor(query.Before)
+	if err != nil {
+		return nil, err
+	}
+
+	strategy := ranking.Assign(userID, r.strategies)
+	page := &models.TimelinePage{Tweets: []*models.Timeline{}, Strategy: strategy.Name()}
+
+	hidden, err := r.hiddenAuthors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if c != nil {
+		return r.rankedPage(ctx, userID, c, size, hidden, page)
+	}
+
+	ranked, err := r.rank(ctx, userID, strategy, hidden)
+	if err != nil {
+		return nil, err
+	}
+
+	end := min(size, len(ranked))
+	for _, item := range ranked[:end] {
+		page.Tweets = append(page.Tweets, item.tweet)
+	}
+	if end == len(ranked) {
+		return page, nil
+	}
+
+	// Guardar el orden para servir las páginas siguientes
+	token, err := newRankedToken()
+	if err != nil {
+		return nil, err
+	}
+	tweetIDs := make([]interface{}, len(ranked))
+	for i, item := range ranked {
+		tweetIDs[i] = item.tweet.ID
+	}
+	key := rankedKey(userID, token)
+	pipe := r.redis.TxPipeline()
+	pipe.RPush(ctx, key, tweetIDs...)
+	pipe.Expire(ctx, key, rankedTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("error al guardar la clasificación del timeline: %w", err)
+	}
+
+	page.Before = rankedCursor{token: token, offset: end}.encode()
+
+	return page, nil
+}
+
+// rank puntúa los ranking.candidates tweets más recientes del timeline y los
+// devuelve ordenados por relevancia.
+func (r *Repository) rank(ctx context.Context, userID string, strategy ranking.Strategy, hidden map[string]struct{}) ([]rankedTweet, error) {
+	keys, err := r.timelineKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, _, err := r.rangeEntries(ctx, keys, nil, r.candidates, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	created := make(map[string]float64, len(entries))
+	tweetIDs := make([]string, len(entries))
+	for i, entry := range entries {
+		tweetIDs[i], _ = entry.Member.(string)
+		created[tweetIDs[i]] = entry.Score
+	}
+
+	tweets, err := r.hydrate(ctx, tweetIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(tweets) < len(tweetIDs) {
+		r.removeDeleted(ctx, keys[0], tweetIDs)
+	}
+	tweets = filterAuthors(tweets, hidden)
+
+	affinity, err := r.affinity(ctx, userID, tweets)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	ranked := make([]rankedTweet, len(tweets))
+	for i, tweet := range tweets {
+		candidate := newCandidate(tweet, affinity)
+		candidate.Age = time.Duration(now-int64(created[tweet.ID])) * time.Millisecond
+		ranked[i] = rankedTweet{tweet: tweet, created: created[tweet.ID], score: strategy.Score(candidate)}
+	}
+
+	// A igual relevancia se muestra primero el más reciente
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		if ranked[i].created != ranked[j].created {
+			return ranked[i].created > ranked[j].created
+		}
+		return ranked[i].tweet.ID > ranked[j].tweet.ID
+	})
+
+	return ranked, nil
+}
+
+// rankedPage lee la página del cursor de la clasificación guardada por la
+// primera página. Los tweets eliminados o de autores ocultos desde entonces
+// se omiten, por lo que una página puede traer menos de size tweets.
+func (r *Repository) rankedPage(ctx context.Context, userID string, c *rankedCursor, size int, hidden map[string]struct{}, page *models.TimelinePage) (*models.TimelinePage, error) {
+	key := rankedKey(userID, c.token)
+
+	pipe := r.redis.Pipeline()
+	ids := pipe.LRange(ctx, key, int64(c.offset), int64(c.offset+size-1))
+	length := pipe.LLen(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("error al leer la clasificación del timeline: %w", err)
+	}
+	if length.Val() == 0 {
+		return nil, models.ErrRankingExpired
+	}
+	if len(ids.Val()) == 0 {
+		return page, nil
+	}
+
+	tweets, err := r.hydrate(ctx, ids.Val())
+	if err != nil {
+		return nil, err
+	}
+	page.Tweets = append(page.Tweets, filterAuthors(tweets, hidden)...)
+
+	end := c.offset + len(ids.Val())
+	if int64(end) < length.Val() {
+		page.Before = rankedCursor{token: c.token, offset: end}.encode()
+	}
+
+	return page, nil
+}
+
+// newRankedToken genera el identificador aleatorio de una clasificación.
+func newRankedToken() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("error al generar el token de la clasificación: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// affinity devuelve la afinidad del usuario con los autores de los tweets y
+// de los tweets que retuitean o citan.
+func (r *Repository) affinity(ctx context.Context, userID string, tweets []*models.Timeline) (map[string]float64, error) {
+	authors := make([]string, 0)
+	seen := make(map[string]struct{})
+	add := func(authorID string) {
+		if _, ok := seen[authorID]; !ok {
+			seen[authorID] = struct{}{}
+			authors = append(authors, authorID)
+		}
+	}
+	for _, tweet := range tweets {
+		add(tweet.UserID)
+		if tweet.Original != nil {
+			add(tweet.Original.UserID)
+		}
+	}
+
+	affinity := make(map[string]float64, len(authors))
+	if len(authors) == 0 {
+		return affinity, nil
+	}
+
+	scores, err := r.redis.ZMScore(ctx, affinityKey(userID), authors...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error al recuperar la afinidad con los autores: %w", err)
+	}
+	for i, authorID := range authors {
+		affinity[authorID] = scores[i]
+	}
+
+	return affinity, nil
+}
+
+// newCandidate reúne las señales del tweet. Un retweet no tiene interacciones
+// propias, por lo que se usan las del original.
+func newCandidate(tweet *models.Timeline, affinity map[string]float64) ranking.Candidate {
+	candidate := ranking.Candidate{
+		Likes:    tweet.Likes,
+		Shares:   tweet.Shares,
+		Comments: tweet.Comments,
+		Affinity: affinity[tweet.UserID],
+	}
+
+	if tweet.Original != nil {
+		if tweet.Kind == models.TweetKindRetweet {
+			candidate.Likes = tweet.Original.Likes
+			candidate.Shares = tweet.Original.Shares
+			candidate.Comments = tweet.Original.Comments
+		}
+		candidate.Affinity = max(candidate.Affinity, affinity[tweet.Original.UserID])
+	}
+
+	return candidate
+}
@@ -4,38 +4,508 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"sort"
+	"strconv"
 	"timeline-service/internal/domain/models"
+	"timeline-service/internal/domain/ranking"
+	"timeline-service/internal/infrastructure/keys"
 	"timeline-service/internal/interfaces"
 
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	defaultPageSize = 10
+	// maxFillAttempts limita las lecturas adicionales para completar una página
+	maxFillAttempts = 3
+)
+
 type Repository struct {
 	redis *redis.Client
+	// timelineLength es la longitud máxima de cada timeline en Redis
+	timelineLength int
+	// strategies son las estrategias de ranking entre las que se reparten los lectores
+	strategies []ranking.Strategy
+	// candidates es el número de tweets recientes que se puntúan en modo ranked
+	candidates int
+	// hub reparte los anuncios de tweets nuevos entre las conexiones de GET /stream
+	hub *hub
+}
+
+func NewRepository(redis *redis.Client, timelineLength int, strategies []ranking.Strategy, candidates int) interfaces.Repository {
+	return &Repository{
+		redis:          redis,
+		timelineLength: timelineLength,
+		strategies:     strategies,
+		candidates:     candidates,
+		hub:            newHub(redis),
+	}
+}
+
+func (r *Repository) Paginate(ctx context.Context, userID string, query *models.TimelineQuery) (*models.TimelinePage, error) {
+	if query.Mode == models.TimelineModeRanked {
+		return r.paginateRanked(ctx, userID, query)
+	}
+
+	size := query.Size
+	if size <= 0 {
+		size = defaultPageSize
+	}
+
+	before, err := decodeCursor(query.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := decodeCursor(query.After)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := r.timelineKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Autores silenciados o bloqueados por el lector, o que lo bloquearon
+	hidden, err := r.hiddenAuthors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	newer := after != nil
+	position := before
+	if newer {
+		position = after
+	}
+
+	page := &models.TimelinePage{Tweets: []*models.Timeline{}}
+	var newest, oldest *cursor
+	var hasMore, rebuilt bool
+
+	// Los tweets eliminados u omitidos se reemplazan leyendo más entradas
+	// hasta completar la página
+	for attempt := 0; attempt < maxFillAttempts && len(page.Tweets) < size; attempt++ {
+		entries, more, err := r.rangeEntries(ctx, keys, position, size-len(page.Tweets), newer)
+		if err != nil {
+			return nil, err
+		}
+
+		// Al pasar de los tweets guardados en el timeline, la página se reconstruye
+		// con el índice de tweets por autor que mantiene el tweets-service
+		if !newer && !rebuilt {
+			trimmed, err := r.pastTimeline(ctx, keys[0], entries, size-len(page.Tweets), more)
+			if err != nil {
+				return nil, err
+			}
+			if trimmed {
+				rebuilt = true
+				if keys, err = r.followingKeys(ctx, userID, keys[0]); err != nil {
+					return nil, err
+				}
+				if entries, more, err = r.rangeEntries(ctx, keys, position, size-len(page.Tweets), newer); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		hasMore = more
+		if len(entries) == 0 {
+			break
+		}
+
+		tweetIDs := make([]string, len(entries))
+		for i, entry := range entries {
+			tweetIDs[i], _ = entry.Member.(string)
+		}
+
+		tweets, err := r.hydrate(ctx, tweetIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(tweets) < len(tweetIDs) {
+			r.removeDeleted(ctx, keys[0], tweetIDs)
+		}
+		tweets = filterAuthors(tweets, hidden)
+
+		// Los cursores se calculan sobre los IDs leídos aunque algún tweet se omita
+		first := &cursor{score: entries[0].Score, id: tweetIDs[0]}
+		last := &cursor{score: entries[len(entries)-1].Score, id: tweetIDs[len(tweetIDs)-1]}
+		if newer {
+			page.Tweets = append(tweets, page.Tweets...)
+			newest, position = first, first
+			if oldest == nil {
+				oldest = last
+			}
+		} else {
+			page.Tweets = append(page.Tweets, tweets...)
+			oldest, position = last, last
+			if newest == nil {
+				newest = first
+			}
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	if newest == nil {
+		// No hay tweets para procesar; se conserva el cursor para seguir consultando
+		page.After = query.After
+		return page, nil
+	}
+
+	page.After = newest.encode()
+	if hasMore || newer {
+		page.Before = oldest.encode()
+	}
+
+	return page, nil
+}
+
+// timelineKeys devuelve el timeline del usuario seguido de los índices de
+// tweets de los autores seguidos cuyos tweets no se distribuyen al escribir,
+// que se mezclan al leer.
+func (r *Repository) timelineKeys(ctx context.Context, userID string) ([]string, error) {
+	celebrityIDs, err := r.followedCelebrities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{fmt.Sprintf("timeline:%s", userID)}
+	for _, celebrityID := range celebrityIDs {
+		keys = append(keys, fmt.Sprintf("user_tweets:%s", celebrityID))
+	}
+
+	return keys, nil
+}
+
+// followedCelebrities devuelve los autores seguidos por el usuario cuyos
+// tweets no se distribuyen al escribir.
+func (r *Repository) followedCelebrities(ctx context.Context, userID string) ([]string, error) {
+	celebrityIDs, err := r.redis.SInter(ctx, fmt.Sprintf("following:%s", userID), keys.Celebrities).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error al recuperar los autores con muchos seguidores: %w", err)
+	}
+
+	return celebrityIDs, nil
 }
 
-func NewRepository(redis *redis.Client) interfaces.Repository {
-	return &Repository{redis: redis}
+// hiddenAuthors devuelve los autores cuyos tweets no deben mostrarse al
+// usuario. Los bloqueos y silencios los mantiene el user-service en Redis.
+func (r *Repository) hiddenAuthors(ctx context.Context, userID string) (map[string]struct{}, error) {
+	authorIDs, err := r.redis.SUnion(ctx,
+		fmt.Sprintf("muting:%s", userID),
+		fmt.Sprintf("blocking:%s", userID),
+		fmt.Sprintf("blocked_by:%s", userID),
+	).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error al recuperar los usuarios bloqueados y silenciados: %w", err)
+	}
+
+	hidden := make(map[string]struct{}, len(authorIDs))
+	for _, authorID := range authorIDs {
+		hidden[authorID] = struct{}{}
+	}
+
+	return hidden, nil
 }
 
-func (r *Repository) Paginate(ctx context.Context, userID string, page, size int) ([]*models.Timeline, error) {
+// filterAuthors descarta los tweets de los autores ocultos, incluidos los
+// retweets y citas de sus tweets.
+func filterAuthors(tweets []*models.Timeline, hidden map[string]struct{}) []*models.Timeline {
+	if len(hidden) == 0 {
+		return tweets
+	}
 
-	start := (page - 1) * size
-	end := start + size - 1
+	kept := tweets[:0]
+	for _, tweet := range tweets {
+		if _, ok := hidden[tweet.UserID]; ok {
+			continue
+		}
+		if tweet.Original != nil {
+			if _, ok := hidden[tweet.Original.UserID]; ok {
+				continue
+			}
+		}
+		kept = append(kept, tweet)
+	}
 
-	timelineKey := fmt.Sprintf("timeline:%s", userID)
+	return kept
+}
 
-	// Obtener los IDs de los tweets desde Redis
-	tweetIDs, err := r.redis.LRange(ctx, timelineKey, int64(start), int64(end)).Result()
+// followingKeys devuelve el timeline junto con el índice de tweets de cada
+// usuario seguido. El tweets-service mantiene user_tweets:<id> con todos los
+// tweets de su almacenamiento, sin recortarlo, y lo completa desde SQLite al
+// arrancar, por lo que sirve para reconstruir las páginas antiguas.
+func (r *Repository) followingKeys(ctx context.Context, userID, timelineKey string) ([]string, error) {
+	following, err := r.redis.SMembers(ctx, fmt.Sprintf("following:%s", userID)).Result()
 	if err != nil {
-		return nil, fmt.Errorf("error al recuperar el timeline: %w", err)
+		return nil, fmt.Errorf("error al recuperar los usuarios seguidos: %w", err)
+	}
+
+	keys := []string{timelineKey}
+	for _, followingID := range following {
+		keys = append(keys, fmt.Sprintf("user_tweets:%s", followingID))
 	}
 
-	if len(tweetIDs) == 0 {
-		// No hay tweets para procesar
-		return []*models.Timeline{}, nil
+	return keys, nil
+}
+
+// removeDeleted retira del timeline los tweets eliminados, por ejemplo si se
+// eliminaron mientras se procesaba su borrado. Como en el cron, solo la marca
+// deleted_tweets:<id> confirma el borrado: un tweet que falta en la caché sin
+// esa marca (tras vaciar Redis o antes de que el tweets-service la complete)
+// solo se omite al hidratar.
+func (r *Repository) removeDeleted(ctx context.Context, timelineKey string, tweetIDs []string) {
+	pipe := r.redis.Pipeline()
+	exists := make([]*redis.IntCmd, len(tweetIDs))
+	tombstones := make([]*redis.IntCmd, len(tweetIDs))
+	for i, tweetID := range tweetIDs {
+		exists[i] = pipe.Exists(ctx, fmt.Sprintf("tweets:%s", tweetID))
+		tombstones[i] = pipe.Exists(ctx, fmt.Sprintf("deleted_tweets:%s", tweetID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Error al comprobar los tweets eliminados: %v", err)
+		return
+	}
+
+	deleted := make([]interface{}, 0)
+	for i := range tweetIDs {
+		if exists[i].Val() == 0 && tombstones[i].Val() > 0 {
+			deleted = append(deleted, tweetIDs[i])
+		}
+	}
+	if len(deleted) == 0 {
+		return
+	}
+
+	if err := r.redis.ZRem(ctx, timelineKey, deleted...).Err(); err != nil {
+		log.Printf("Error al retirar los tweets eliminados del timeline: %v", err)
+	}
+}
+
+// trimScript recorta el timeline a su longitud máxima y, si descarta algún
+// tweet, lo marca como recortado para que al paginar más allá se reconstruyan
+// las páginas.
+var trimScript = redis.NewScript(`
+local removed = redis.call("ZREMRANGEBYRANK", KEYS[1], 0, -tonumber(ARGV[1]) - 1)
+if removed > 0 then
+	redis.call("SET", KEYS[2], 1)
+end
+return removed
+`)
+
+// trimmedKey es la marca de un timeline que perdió tweets al recortarse.
+func trimmedKey(timelineKey string) string {
+	return fmt.Sprintf("trimmed:%s", timelineKey)
+}
+
+// TrimTimeline descarta los tweets más antiguos que exceden la longitud
+// máxima del timeline.
+func TrimTimeline(ctx context.Context, pipe redis.Pipeliner, timelineKey string, length int) {
+	if length <= 0 {
+		return
+	}
+	trimScript.Eval(ctx, pipe, []string{timelineKey, trimmedKey(timelineKey)}, length)
+}
+
+// pastTimeline indica si la página leída llega más allá del tweet más antiguo
+// que conserva el timeline después de recortarse a su longitud máxima.
+func (r *Repository) pastTimeline(ctx context.Context, timelineKey string, entries []redis.Z, size int, hasMore bool) (bool, error) {
+	if r.timelineLength <= 0 {
+		return false, nil
+	}
+
+	// Un timeline que nunca perdió tweets al recortarse está completo aunque
+	// tenga la longitud máxima
+	trimmed, err := r.redis.Exists(ctx, trimmedKey(timelineKey)).Result()
+	if err != nil {
+		return false, fmt.Errorf("error al recuperar el timeline: %w", err)
+	}
+	if trimmed == 0 {
+		return false, nil
 	}
 
+	if !hasMore || len(entries) < size {
+		return true, nil
+	}
+
+	oldest, err := r.redis.ZRangeWithScores(ctx, timelineKey, 0, 0).Result()
+	if err != nil {
+		return false, fmt.Errorf("error al recuperar el timeline: %w", err)
+	}
+
+	return len(oldest) > 0 && entries[len(entries)-1].Score < oldest[0].Score, nil
+}
+
+// rangeEntries lee de los sorted sets indicados hasta size tweets más
+// antiguos (o más recientes si newer) que el cursor y los devuelve del más
+// reciente al más antiguo, sin duplicados. Indica además si quedan más tweets.
+// El primero de keys es el timeline del lector; si aún se guarda como lista
+// se migra y se vuelve a leer.
+func (r *Repository) rangeEntries(ctx context.Context, keys []string, c *cursor, size int, newer bool) ([]redis.Z, bool, error) {
+	entries, more, err := r.readEntries(ctx, keys, c, size, newer)
+	if !isWrongType(err) {
+		return entries, more, err
+	}
+
+	if err := r.migrateTimeline(ctx, keys[0]); err != nil {
+		return nil, false, fmt.Errorf("error al migrar el timeline: %w", err)
+	}
+	return r.readEntries(ctx, keys, c, size, newer)
+}
+
+func (r *Repository) readEntries(ctx context.Context, keys []string, c *cursor, size int, newer bool) ([]redis.Z, bool, error) {
+	bound := ""
+	ties := make([]int64, len(keys))
+	if c != nil {
+		// Los tweets con la misma puntuación que el cursor se leen y se
+		// descartan después comparando su ID
+		bound = strconv.FormatInt(int64(c.score), 10)
+
+		pipe := r.redis.Pipeline()
+		cmds := make([]*redis.IntCmd, len(keys))
+		for i, key := range keys {
+			cmds[i] = pipe.ZCount(ctx, key, bound, bound)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, false, fmt.Errorf("error al recuperar el timeline: %w", err)
+		}
+		for i, cmd := range cmds {
+			ties[i] = cmd.Val()
+		}
+	}
+
+	pipe := r.redis.Pipeline()
+	cmds := make([]*redis.ZSliceCmd, len(keys))
+	for i, key := range keys {
+		by := &redis.ZRangeBy{Min: "-inf", Max: "+inf", Count: int64(size+1) + ties[i]}
+		if newer {
+			if c != nil {
+				by.Min = bound
+			}
+			cmds[i] = pipe.ZRangeByScoreWithScores(ctx, key, by)
+		} else {
+			if c != nil {
+				by.Max = bound
+			}
+			cmds[i] = pipe.ZRevRangeByScoreWithScores(ctx, key, by)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, false, fmt.Errorf("error al recuperar el timeline: %w", err)
+	}
+
+	seen := make(map[string]struct{})
+	entries := make([]redis.Z, 0)
+	for _, cmd := range cmds {
+		for _, z := range cmd.Val() {
+			tweetID, _ := z.Member.(string)
+			if _, ok := seen[tweetID]; ok {
+				continue
+			}
+			if c != nil && (newer && !c.greater(z.Score, tweetID) || !newer && !c.less(z.Score, tweetID)) {
+				continue
+			}
+			seen[tweetID] = struct{}{}
+			entries = append(entries, z)
+		}
+	}
+
+	// Ordenar alejándose del cursor para quedarse con los más cercanos
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Score != b.Score {
+			return (a.Score > b.Score) != newer
+		}
+		return (a.Member.(string) > b.Member.(string)) != newer
+	})
+
+	hasMore := len(entries) > size
+	if hasMore {
+		entries = entries[:size]
+	}
+
+	if newer {
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+	}
+
+	return entries, hasMore, nil
+}
+
+// hydrate construye las entradas del timeline a partir de los IDs de tweets,
+// resolviendo el tweet original de retweets y citas y los autores de ambos.
+func (r *Repository) hydrate(ctx context.Context, tweetIDs []string) ([]*models.Timeline, error) {
+	tweets, err := r.getTweets(ctx, tweetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recopilar los tweets referenciados por retweets y citas
+	referenceIDs := make([]string, 0)
+	for _, tweet := range tweets {
+		if tweet.ReferenceID != "" {
+			referenceIDs = append(referenceIDs, tweet.ReferenceID)
+		}
+	}
+
+	originals := make(map[string]*models.Tweet)
+	if len(referenceIDs) > 0 {
+		referenced, err := r.getTweets(ctx, referenceIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, tweet := range referenced {
+			originals[tweet.ID] = tweet
+		}
+	}
+
+	// Map para mantener los IDs de usuario únicos
+	userIDSet := make(map[string]struct{})
+	for _, tweet := range tweets {
+		userIDSet[tweet.UserID] = struct{}{}
+	}
+	for _, tweet := range originals {
+		userIDSet[tweet.UserID] = struct{}{}
+	}
+
+	userMap, err := r.getUsers(ctx, userIDSet)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construir el timeline
+	timeline := make([]*models.Timeline, 0, len(tweets))
+	for _, tweet := range tweets {
+		entry := newTimeline(tweet, userMap)
+		if entry == nil {
+			// Usuario no encontrado, omitir este tweet
+			continue
+		}
+
+		if tweet.ReferenceID != "" {
+			if original, ok := originals[tweet.ReferenceID]; ok {
+				entry.Original = newTimeline(original, userMap)
+			}
+			if entry.Original == nil && tweet.Kind == models.TweetKindRetweet {
+				// Un retweet sin su original no tiene contenido que mostrar
+				continue
+			}
+		}
+
+		timeline = append(timeline, entry)
+	}
+
+	return timeline, nil
+}
+
+// getTweets obtiene de Redis los tweets indicados conservando su orden. Los
+// tweets que ya no existen se omiten.
+func (r *Repository) getTweets(ctx context.Context, tweetIDs []string) ([]*models.Tweet, error) {
 	// Construir las claves de los tweets
 	tweetKeys := make([]string, len(tweetIDs))
 	for i, tweetID := range tweetIDs {
@@ -48,11 +518,7 @@ func (r *Repository) Paginate(ctx context.Context, userID string, page, size int
 		return nil, fmt.Errorf("error al recuperar los tweets: %w", err)
 	}
 
-	// Map para mantener los IDs de usuario únicos
-	userIDSet := make(map[string]struct{})
-	// Slice para mantener los tweets
 	tweets := make([]*models.Tweet, 0, len(tweetDataList))
-
 	for i, tweetData := range tweetDataList {
 		if tweetData == nil {
 			// El tweet no existe
@@ -69,7 +535,16 @@ func (r *Repository) Paginate(ctx context.Context, userID string, page, size int
 		}
 		tweet.ID = tweetIDs[i]
 		tweets = append(tweets, &tweet)
-		userIDSet[tweet.UserID] = struct{}{}
+	}
+
+	return tweets, nil
+}
+
+// getUsers obtiene de Redis los autores indicados indexados por su ID.
+func (r *Repository) getUsers(ctx context.Context, userIDSet map[string]struct{}) (map[string]*models.User, error) {
+	userMap := make(map[string]*models.User)
+	if len(userIDSet) == 0 {
+		return userMap, nil
 	}
 
 	// Recopilar los IDs de usuario únicos
@@ -90,8 +565,6 @@ func (r *Repository) Paginate(ctx context.Context, userID string, page, size int
 		return nil, fmt.Errorf("error al recuperar los usuarios: %w", err)
 	}
 
-	// Map para mantener los usuarios
-	userMap := make(map[string]*models.User)
 	for i, userData := range userDataList {
 		if userData == nil {
 			// El usuario no existe
@@ -110,26 +583,30 @@ func (r *Repository) Paginate(ctx context.Context, userID string, page, size int
 		userMap[userIDs[i]] = &user
 	}
 
-	// Construir el timeline
-	timeline := make([]*models.Timeline, 0, len(tweets))
-	for _, tweet := range tweets {
-		user, ok := userMap[tweet.UserID]
-		if !ok {
-			// Usuario no encontrado, omitir este tweet
-			continue
-		}
-		timeline = append(timeline, &models.Timeline{
-			ID:       tweet.ID,
-			Content:  tweet.Content,
-			Likes:    tweet.Likes,
-			Shares:   tweet.Shares,
-			Comments: tweet.Comments,
-			UserID:   tweet.UserID,
-			Name:     user.Name,
-			Nickname: user.Nickname,
-			Avatar:   user.Avatar,
-		})
+	return userMap, nil
+}
+
+func newTimeline(tweet *models.Tweet, userMap map[string]*models.User) *models.Timeline {
+	user, ok := userMap[tweet.UserID]
+	if !ok {
+		return nil
 	}
 
-	return timeline, nil
+	kind := tweet.Kind
+	if kind == "" {
+		kind = models.TweetKindOriginal
+	}
+
+	return &models.Timeline{
+		ID:       tweet.ID,
+		Kind:     kind,
+		Content:  tweet.Content,
+		Likes:    tweet.Likes,
+		Shares:   tweet.Shares,
+		Comments: tweet.Comments,
+		UserID:   tweet.UserID,
+		Name:     user.Name,
+		Nickname: user.Nickname,
+		Avatar:   user.Avatar,
+	}
 }
@@ -1 +1,428 @@
 package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+	"timeline-service/internal/domain/models"
+	"timeline-service/internal/domain/ranking"
+	"timeline-service/internal/infrastructure/keys"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRepository(t *testing.T) (*Repository, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	return NewRepository(client, 5, nil, 10).(*Repository), server
+}
+
+// addTweet guarda el tweet y su autor como lo hacen el tweets-service y el
+// user-service, y devuelve su puntuación en el timeline.
+func addTweet(t *testing.T, server *miniredis.Miniredis, id, userID string, createdAt time.Time) float64 {
+	data, err := json.Marshal(models.Tweet{UserID: userID, Kind: models.TweetKindOriginal, Content: "Tweet " + id, CreatedAt: createdAt})
+	assert.NoError(t, err)
+	assert.NoError(t, server.Set("tweets:"+id, string(data)))
+	assert.NoError(t, server.Set("users:"+userID, `{"id":"`+userID+`","name":"Autor","nickname":"`+userID+`"}`))
+
+	return float64(createdAt.UnixMilli())
+}
+
+// tweetIDs devuelve los IDs de la página en orden.
+func tweetIDs(page *models.TimelinePage) []string {
+	ids := make([]string, len(page.Tweets))
+	for i, tweet := range page.Tweets {
+		ids[i] = tweet.ID
+	}
+	return ids
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := cursor{score: 1715342400000, id: "abc"}
+
+	decoded, err := decodeCursor(c.encode())
+	assert.NoError(t, err)
+	assert.Equal(t, c, *decoded)
+
+	decoded, err = decodeCursor("")
+	assert.NoError(t, err)
+	assert.Nil(t, decoded)
+
+	for _, value := range []string{"no es un cursor", "MTIz", "YWJjOmlk"} {
+		_, err = decodeCursor(value)
+		assert.ErrorIs(t, err, models.ErrInvalidCursor, value)
+	}
+
+	// Los tweets con la misma puntuación se ordenan por ID
+	assert.True(t, c.less(c.score, "abb"))
+	assert.False(t, c.less(c.score, "abc"))
+	assert.True(t, c.greater(c.score+1, "aaa"))
+	assert.False(t, c.greater(c.score, "abb"))
+}
+
+func TestRankedCursor_RoundTrip(t *testing.T) {
+	c := rankedCursor{token: "a1b2c3", offset: 20}
+
+	decoded, err := decodeRankedCursor(c.encode())
+	assert.NoError(t, err)
+	assert.Equal(t, c, *decoded)
+
+	_, err = decodeRankedCursor(cursor{score: 1, id: "abc"}.encode())
+	assert.ErrorIs(t, err, models.ErrInvalidCursor)
+
+	_, err = decodeRankedCursor(rankedCursor{token: "a1b2c3", offset: -1}.encode())
+	assert.ErrorIs(t, err, models.ErrInvalidCursor)
+}
+
+func TestRepository_RangeEntries(t *testing.T) {
+	repo, server := newTestRepository(t)
+	ctx := context.Background()
+
+	// El timeline y el índice de un autor mezclado al leer comparten un tweet
+	// y dos tweets empatan en puntuación
+	server.ZAdd("timeline:reader", 5, "e")
+	server.ZAdd("timeline:reader", 3, "c")
+	server.ZAdd("timeline:reader", 3, "b")
+	server.ZAdd("user_tweets:celebrity", 4, "d")
+	server.ZAdd("user_tweets:celebrity", 3, "c")
+	server.ZAdd("user_tweets:celebrity", 1, "a")
+	keys := []string{"timeline:reader", "user_tweets:celebrity"}
+
+	ids := func(entries []redis.Z) []string {
+		result := make([]string, len(entries))
+		for i, entry := range entries {
+			result[i] = entry.Member.(string)
+		}
+		return result
+	}
+
+	// Hacia atrás, del más reciente al más antiguo y sin duplicados
+	entries, more, err := repo.rangeEntries(ctx, keys, nil, 3, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"e", "d", "c"}, ids(entries))
+	assert.True(t, more)
+
+	// El cursor sobre un empate continúa con el otro tweet de la misma puntuación
+	entries, more, err = repo.rangeEntries(ctx, keys, &cursor{score: 3, id: "c"}, 3, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, ids(entries))
+	assert.False(t, more)
+
+	// Hacia adelante se devuelven los más cercanos al cursor, también del más
+	// reciente al más antiguo
+	entries, more, err = repo.rangeEntries(ctx, keys, &cursor{score: 1, id: "a"}, 2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "b"}, ids(entries))
+	assert.True(t, more)
+
+	entries, more, err = repo.rangeEntries(ctx, keys, &cursor{score: 5, id: "e"}, 2, true)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.False(t, more)
+}
+
+func TestFilterAuthors(t *testing.T) {
+	tweets := []*models.Timeline{
+		{ID: "a", UserID: "amigo"},
+		{ID: "b", UserID: "bloqueado"},
+		{ID: "c", UserID: "amigo", Kind: models.TweetKindRetweet, Original: &models.Timeline{ID: "x", UserID: "silenciado"}},
+		{ID: "d", UserID: "amigo", Kind: models.TweetKindQuote, Original: &models.Timeline{ID: "y", UserID: "otro"}},
+	}
+
+	// Sin autores ocultos se devuelve la misma lista
+	assert.Len(t, filterAuthors(tweets, nil), 4)
+
+	// Se descartan los tweets de los autores ocultos y los que comparten sus tweets
+	hidden := map[string]struct{}{"bloqueado": {}, "silenciado": {}}
+	kept := filterAuthors(tweets, hidden)
+	ids := make([]string, len(kept))
+	for i, tweet := range kept {
+		ids[i] = tweet.ID
+	}
+	assert.Equal(t, []string{"a", "d"}, ids)
+}
+
+func TestRepository_Paginate(t *testing.T) {
+	repo, server := newTestRepository(t)
+	ctx := context.Background()
+
+	base := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	for i, id := range []string{"uno", "dos", "tres", "cuatro"} {
+		score := addTweet(t, server, id, "author", base.Add(time.Duration(i)*time.Minute))
+		server.ZAdd("timeline:reader", score, id)
+	}
+	// Un tweet eliminado se retira del timeline y se reemplaza
+	server.ZAdd("timeline:reader", float64(base.Add(90*time.Second).UnixMilli()), "borrado")
+	server.Set("deleted_tweets:borrado", "1")
+	// Sin la marca de borrado, un tweet que falta en la caché solo se omite
+	server.ZAdd("timeline:reader", float64(base.Add(30*time.Second).UnixMilli()), "sin-cache")
+
+	first, err := repo.Paginate(ctx, "reader", &models.TimelineQuery{Size: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cuatro", "tres"}, tweetIDs(first))
+	assert.NotEmpty(t, first.Before)
+
+	second, err := repo.Paginate(ctx, "reader", &models.TimelineQuery{Size: 2, Before: first.Before})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dos", "uno"}, tweetIDs(second))
+
+	_, err = repo.redis.ZScore(ctx, "timeline:reader", "borrado").Result()
+	assert.ErrorIs(t, err, redis.Nil)
+	_, err = repo.redis.ZScore(ctx, "timeline:reader", "sin-cache").Result()
+	assert.NoError(t, err)
+
+	// Los tweets nuevos se piden con el cursor más reciente
+	score := addTweet(t, server, "cinco", "author", base.Add(time.Hour))
+	server.ZAdd("timeline:reader", score, "cinco")
+	newer, err := repo.Paginate(ctx, "reader", &models.TimelineQuery{After: first.After})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cinco"}, tweetIDs(newer))
+
+	_, err = repo.Paginate(ctx, "reader", &models.TimelineQuery{Before: "no es un cursor"})
+	assert.ErrorIs(t, err, models.ErrInvalidCursor)
+}
+
+func TestRepository_PaginateRanked(t *testing.T) {
+	repo, server := newTestRepository(t)
+	ctx := context.Background()
+
+	base := time.Now().Add(-time.Hour)
+	ids := []string{"uno", "dos", "tres", "cuatro", "cinco"}
+	for i, id := range ids {
+		score := addTweet(t, server, id, "author", base.Add(time.Duration(i)*time.Minute))
+		server.ZAdd("timeline:reader", score, id)
+	}
+
+	first, err := repo.Paginate(ctx, "reader", &models.TimelineQuery{Mode: models.TimelineModeRanked, Size: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, ranking.Default, first.Strategy)
+	assert.Len(t, first.Tweets, 2)
+	assert.NotEmpty(t, first.Before)
+
+	// Ni un tweet nuevo ni un cambio de puntuación alteran las páginas siguientes
+	score := addTweet(t, server, "nuevo", "author", time.Now())
+	server.ZAdd("timeline:reader", score, "nuevo")
+	data, err := json.Marshal(models.Tweet{UserID: "author", Kind: models.TweetKindOriginal, Content: "Tweet uno", Likes: 1000, CreatedAt: base})
+	assert.NoError(t, err)
+	assert.NoError(t, server.Set("tweets:uno", string(data)))
+
+	seen := tweetIDs(first)
+	before := first.Before
+	for before != "" {
+		page, err := repo.Paginate(ctx, "reader", &models.TimelineQuery{Mode: models.TimelineModeRanked, Size: 2, Before: before})
+		assert.NoError(t, err)
+		seen = append(seen, tweetIDs(page)...)
+		before = page.Before
+	}
+	assert.ElementsMatch(t, ids, seen)
+	assert.Len(t, seen, len(ids))
+
+	// La clasificación guardada caduca
+	server.FastForward(rankedTTL + time.Second)
+	_, err = repo.Paginate(ctx, "reader", &models.TimelineQuery{Mode: models.TimelineModeRanked, Size: 2, Before: first.Before})
+	assert.ErrorIs(t, err, models.ErrRankingExpired)
+
+	// Una primera página que lo contiene todo no guarda la clasificación
+	all, err := repo.Paginate(ctx, "reader", &models.TimelineQuery{Mode: models.TimelineModeRanked, Size: 10})
+	assert.NoError(t, err)
+	assert.Len(t, all.Tweets, len(ids)+1)
+	assert.Empty(t, all.Before)
+
+	_, err = repo.Paginate(ctx, "reader", &models.TimelineQuery{Mode: models.TimelineModeRanked, Before: "no es un cursor"})
+	assert.ErrorIs(t, err, models.ErrInvalidCursor)
+}
+
+func TestRepository_Paginate_LegacyList(t *testing.T) {
+	repo, server := newTestRepository(t)
+	ctx := context.Background()
+
+	// Los timelines anteriores son listas con el tweet más reciente primero y
+	// los tweets más antiguos no guardaban su fecha de creación
+	base := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	addTweet(t, server, "nuevo", "author", base)
+	addTweet(t, server, "viejo", "author", time.Time{})
+	addTweet(t, server, "muy-viejo", "author", time.Time{})
+	server.RPush("timeline:reader", "nuevo", "borrado", "viejo", "muy-viejo")
+
+	// El timeline se migra al leerlo, sin que la lectura falle
+	page, err := repo.Paginate(ctx, "reader", &models.TimelineQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"nuevo", "viejo", "muy-viejo"}, tweetIDs(page))
+
+	entries, err := repo.redis.ZRevRangeWithScores(ctx, "timeline:reader", 0, -1).Result()
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, float64(base.UnixMilli()), entries[0].Score)
+		assert.Equal(t, entries[0].Score-1, entries[1].Score)
+		assert.Equal(t, entries[1].Score-1, entries[2].Score)
+	}
+}
+
+func TestRepository_MigrateTimelines(t *testing.T) {
+	repo, server := newTestRepository(t)
+	ctx := context.Background()
+
+	base := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		id := fmt.Sprintf("tweet-%d", i)
+		addTweet(t, server, id, "author", base.Add(time.Duration(i)*time.Minute))
+		server.Lpush("timeline:reader", id)
+	}
+	server.ZAdd("timeline:other", 1, "tweet-0")
+
+	repo.MigrateTimelines(ctx)
+
+	// Se conservan los timeline.max_length tweets más recientes
+	timeline, err := repo.redis.ZRevRange(ctx, "timeline:reader", 0, -1).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tweet-6", "tweet-5", "tweet-4", "tweet-3", "tweet-2"}, timeline)
+
+	other, err := repo.redis.ZRange(ctx, "timeline:other", 0, -1).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tweet-0"}, other)
+}
+
+func TestRepository_Paginate_Trimmed(t *testing.T) {
+	repo, server := newTestRepository(t)
+	ctx := context.Background()
+
+	server.SAdd("following:reader", "author")
+	base := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	var all []string
+	for i := 0; i < 7; i++ {
+		id := fmt.Sprintf("tweet-%d", i)
+		score := addTweet(t, server, id, "author", base.Add(time.Duration(i)*time.Minute))
+		server.ZAdd("user_tweets:author", score, id)
+		all = append([]string{id}, all...)
+
+		// Cada tweet se distribuye y el timeline se recorta como en el cron
+		pipe := repo.redis.TxPipeline()
+		pipe.ZAdd(ctx, "timeline:reader", redis.Z{Score: score, Member: id})
+		TrimTimeline(ctx, pipe, "timeline:reader", repo.timelineLength)
+		_, err := pipe.Exec(ctx)
+		assert.NoError(t, err)
+
+		// Solo se marca como recortado al descartar tweets
+		assert.Equal(t, i >= repo.timelineLength, server.Exists(trimmedKey("timeline:reader")), id)
+	}
+
+	// Al paginar más allá de los tweets guardados, las páginas se reconstruyen
+	// con el índice de tweets del autor seguido
+	var seen []string
+	query := &models.TimelineQuery{Size: 3}
+	for {
+		page, err := repo.Paginate(ctx, "reader", query)
+		assert.NoError(t, err)
+		seen = append(seen, tweetIDs(page)...)
+		if page.Before == "" {
+			break
+		}
+		query.Before = page.Before
+	}
+	assert.Equal(t, all, seen)
+}
+
+func TestRepository_Paginate_FullNotTrimmed(t *testing.T) {
+	repo, server := newTestRepository(t)
+	ctx := context.Background()
+
+	// Un timeline con exactamente la longitud máxima nunca se recortó: los
+	// tweets anteriores del autor no forman parte de él
+	server.SAdd("following:reader", "author")
+	base := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		id := fmt.Sprintf("tweet-%d", i)
+		score := addTweet(t, server, id, "author", base.Add(time.Duration(i)*time.Minute))
+		server.ZAdd("user_tweets:author", score, id)
+		if i >= 2 {
+			server.ZAdd("timeline:reader", score, id)
+		}
+	}
+
+	page, err := repo.Paginate(ctx, "reader", &models.TimelineQuery{Size: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tweet-6", "tweet-5", "tweet-4", "tweet-3", "tweet-2"}, tweetIDs(page))
+	assert.Empty(t, page.Before)
+}
+
+// addDeadLetter registra un fallo como lo hace el cron.
+func addDeadLetter(t *testing.T, r *Repository, tweetID string, failedAt time.Time) {
+	data, err := json.Marshal(models.DeadLetter{TweetID: tweetID, Error: "fallo", Attempts: 5, FailedAt: failedAt})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	assert.NoError(t, r.redis.Set(ctx, keys.DeadLetter(tweetID), data, 0).Err())
+	assert.NoError(t, r.redis.ZAdd(ctx, keys.DeadLetters, redis.Z{Score: float64(failedAt.UnixMilli()), Member: tweetID}).Err())
+}
+
+func TestRepository_DeadLetters(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	base := time.Now()
+	for i, id := range []string{"uno", "dos", "tres"} {
+		addDeadLetter(t, repo, id, base.Add(time.Duration(i)*time.Second))
+	}
+
+	// Los fallos más recientes primero
+	first, err := repo.DeadLetters(ctx, 1, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, first, 2) {
+		assert.Equal(t, "tres", first[0].TweetID)
+		assert.Equal(t, "dos", first[1].TweetID)
+	}
+
+	second, err := repo.DeadLetters(ctx, 2, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, second, 1) {
+		assert.Equal(t, "uno", second[0].TweetID)
+	}
+
+	empty, err := repo.DeadLetters(ctx, 3, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestRepository_ReplayDeadLetter(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	addDeadLetter(t, repo, "fallido", time.Now())
+
+	assert.NoError(t, repo.ReplayDeadLetter(ctx, "fallido"))
+
+	// El tweet vuelve al stream y sale de la cola de fallidos
+	entries, err := repo.redis.XRange(ctx, keys.TweetStream, "-", "+").Result()
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "fallido", entries[0].Values["tweet_id"])
+	}
+	exists, err := repo.redis.Exists(ctx, keys.DeadLetter("fallido")).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), exists)
+
+	assert.ErrorIs(t, repo.ReplayDeadLetter(ctx, "fallido"), models.ErrDeadLetterNotFound)
+}
+
+func TestRepository_DiscardDeadLetter(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	addDeadLetter(t, repo, "fallido", time.Now())
+
+	assert.NoError(t, repo.DiscardDeadLetter(ctx, "fallido"))
+
+	count, err := repo.redis.ZCard(ctx, keys.DeadLetters).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+	length, err := repo.redis.XLen(ctx, keys.TweetStream).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), length)
+
+	assert.ErrorIs(t, repo.DiscardDeadLetter(ctx, "fallido"), models.ErrDeadLetterNotFound)
+}
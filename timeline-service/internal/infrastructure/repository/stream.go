@@ -0,0 +1,440 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"timeline-service/internal/domain/models"
+	"timeline-service/internal/infrastructure/keys"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// subscriberBuffer es el número de anuncios que se retienen por conexión
+	// mientras el cliente no los consume
+	subscriberBuffer = 64
+	// catchUpSize es el número de tweets que se leen del timeline por vuelta
+	// al recuperar los anuncios perdidos
+	catchUpSize = 100
+	// maxSentIDs limita los IDs enviados que se recuerdan para no repetirlos
+	maxSentIDs = 1000
+	// authorsRefresh es la frecuencia con la que una conexión actualiza los
+	// autores con muchos seguidores a los que está suscrita
+	authorsRefresh = time.Minute
+)
+
+// subscriber es una conexión abierta a GET /stream. Si no consume los
+// anuncios al ritmo en que llegan, se marca como retrasada y recupera los
+// tweets pendientes del timeline en lugar de acumularlos.
+type subscriber struct {
+	updates chan redis.Z
+	lagged  atomic.Bool
+	// channels son los canales a los que está suscrita; los protege hub.subMu
+	channels map[string]struct{}
+}
+
+func newSubscriber() *subscriber {
+	return &subscriber{
+		updates:  make(chan redis.Z, subscriberBuffer),
+		channels: make(map[string]struct{}),
+	}
+}
+
+// notify entrega el anuncio sin bloquear. Un anuncio vacío solo despierta a la
+// conexión para que recupere los tweets del timeline.
+func (s *subscriber) notify(update redis.Z, lagged bool) {
+	if lagged {
+		s.lagged.Store(true)
+	}
+	select {
+	case s.updates <- update:
+	default:
+		// Con el buffer lleno la conexión ya tiene anuncios pendientes que la despertarán
+		s.lagged.Store(true)
+	}
+}
+
+// hub comparte una única conexión pub/sub de Redis entre todas las
+// conexiones de la réplica. Solo se suscribe a los canales que necesitan las
+// conexiones abiertas en ella, y cancela cada canal cuando la última lo deja.
+type hub struct {
+	redis  *redis.Client
+	start  sync.Once
+	pubsub *redis.PubSub
+	// subMu serializa las suscripciones, que esperan a Redis; mu solo protege
+	// el reparto para que los anuncios no esperen a la red
+	subMu       sync.Mutex
+	mu          sync.Mutex
+	subscribers map[string]map[*subscriber]struct{}
+}
+
+func newHub(redis *redis.Client) *hub {
+	return &hub{redis: redis, subscribers: make(map[string]map[*subscriber]struct{})}
+}
+
+// subscribe añade los canales a la conexión. Se registra antes de suscribirse
+// en Redis para no perder los anuncios que lleguen en cuanto se confirme.
+func (h *hub) subscribe(ctx context.Context, sub *subscriber, channels ...string) error {
+	h.start.Do(func() {
+		h.pubsub = h.redis.Subscribe(context.Background())
+		go h.run()
+	})
+
+	h.subMu.Lock()
+	defer h.subMu.Unlock()
+
+	added := make([]string, 0, len(channels))
+	pending := make([]string, 0, len(channels))
+	h.mu.Lock()
+	for _, channel := range channels {
+		if _, ok := sub.channels[channel]; ok {
+			continue
+		}
+		subscribers, ok := h.subscribers[channel]
+		if !ok {
+			subscribers = make(map[*subscriber]struct{})
+			h.subscribers[channel] = subscribers
+			pending = append(pending, channel)
+		}
+		subscribers[sub] = struct{}{}
+		sub.channels[channel] = struct{}{}
+		added = append(added, channel)
+	}
+	h.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	if err := h.pubsub.Subscribe(ctx, pending...); err != nil {
+		h.remove(sub, added)
+		return fmt.Errorf("error al suscribirse a las actualizaciones del timeline: %w", err)
+	}
+
+	return nil
+}
+
+// unsubscribe quita los canales de la conexión, o todos si no se indica
+// ninguno, y cancela en Redis los que ya no usa ninguna conexión.
+func (h *hub) unsubscribe(sub *subscriber, channels ...string) {
+	h.subMu.Lock()
+	defer h.subMu.Unlock()
+
+	if len(channels) == 0 {
+		for channel := range sub.channels {
+			channels = append(channels, channel)
+		}
+	}
+
+	unused := h.remove(sub, channels)
+	if len(unused) == 0 {
+		return
+	}
+	if err := h.pubsub.Unsubscribe(context.Background(), unused...); err != nil {
+		log.Printf("Error al cancelar la suscripción a %v: %v", unused, err)
+	}
+}
+
+// remove quita la conexión de los canales y devuelve los que quedan sin
+// conexiones. Requiere subMu.
+func (h *hub) remove(sub *subscriber, channels []string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var unused []string
+	for _, channel := range channels {
+		if _, ok := sub.channels[channel]; !ok {
+			continue
+		}
+		delete(sub.channels, channel)
+
+		subscribers := h.subscribers[channel]
+		delete(subscribers, sub)
+		if len(subscribers) == 0 {
+			delete(h.subscribers, channel)
+			unused = append(unused, channel)
+		}
+	}
+
+	return unused
+}
+
+// run reparte los anuncios entre las conexiones suscritas a cada canal.
+func (h *hub) run() {
+	for received := range h.pubsub.ChannelWithSubscriptions() {
+		switch msg := received.(type) {
+		case *redis.Subscription:
+			// Tras una reconexión go-redis vuelve a suscribirse; los anuncios
+			// publicados mientras tanto se recuperan del timeline
+			if msg.Kind == "subscribe" {
+				h.dispatch(msg.Channel, redis.Z{}, true)
+			}
+		case *redis.Message:
+			update, err := parseUpdate(msg.Payload)
+			if err != nil {
+				log.Printf("Anuncio inválido en %s: %v", msg.Channel, err)
+				continue
+			}
+			h.dispatch(msg.Channel, update, false)
+		}
+	}
+}
+
+func (h *hub) dispatch(channel string, update redis.Z, lagged bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers[channel] {
+		sub.notify(update, lagged)
+	}
+}
+
+// parseUpdate interpreta un anuncio "<puntuación>:<tweetID>".
+func parseUpdate(payload string) (redis.Z, error) {
+	score, tweetID, found := strings.Cut(payload, ":")
+	if !found || tweetID == "" {
+		return redis.Z{}, fmt.Errorf("formato inesperado %q", payload)
+	}
+
+	n, err := strconv.ParseInt(score, 10, 64)
+	if err != nil {
+		return redis.Z{}, fmt.Errorf("puntuación inválida %q", payload)
+	}
+
+	return redis.Z{Score: float64(n), Member: tweetID}, nil
+}
+
+// timelineStream es el estado de una conexión: el cursor del tweet más
+// reciente enviado, los IDs ya enviados, para no repetirlos al recuperar, y
+// los autores con muchos seguidores cuyos anuncios recibe.
+type timelineStream struct {
+	userID  string
+	last    *cursor
+	sent    map[string]struct{}
+	authors map[string]struct{}
+	events  chan *models.TimelineEvent
+}
+
+// Stream envía los tweets que se distribuyen al timeline de userID, y los de
+// los autores seguidos que no se distribuyen al escribir, hasta que se cancele
+// ctx. Con lastEventID, el cursor del último tweet recibido, se envían antes
+// los tweets posteriores que el cliente se perdió. El canal se cierra al
+// terminar; si falla la lectura de Redis el cliente debe reconectar.
+func (r *Repository) Stream(ctx context.Context, userID, lastEventID string) (<-chan *models.TimelineEvent, error) {
+	last, err := decodeCursor(lastEventID)
+	if err != nil {
+		return nil, err
+	}
+
+	celebrityIDs, err := r.followedCelebrities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// La suscripción empieza antes de recuperar para no perder anuncios
+	sub := newSubscriber()
+	channels := []string{keys.TimelineChannel(userID)}
+	authors := make(map[string]struct{}, len(celebrityIDs))
+	for _, celebrityID := range celebrityIDs {
+		channels = append(channels, keys.AuthorChannel(celebrityID))
+		authors[celebrityID] = struct{}{}
+	}
+	if err := r.hub.subscribe(ctx, sub, channels...); err != nil {
+		r.hub.unsubscribe(sub)
+		return nil, err
+	}
+
+	// Sin cursor se envían los tweets publicados desde la conexión
+	resume := last != nil
+	if !resume {
+		last = &cursor{score: float64(time.Now().UnixMilli())}
+	}
+
+	stream := &timelineStream{
+		userID:  userID,
+		last:    last,
+		sent:    make(map[string]struct{}),
+		authors: authors,
+		events:  make(chan *models.TimelineEvent),
+	}
+
+	go func() {
+		defer close(stream.events)
+		defer r.hub.unsubscribe(sub)
+
+		if err := r.runStream(ctx, stream, sub, resume); err != nil && ctx.Err() == nil {
+			log.Printf("Error en el stream del timeline de %s: %v", userID, err)
+		}
+	}()
+
+	return stream.events, nil
+}
+
+func (r *Repository) runStream(ctx context.Context, stream *timelineStream, sub *subscriber, resume bool) error {
+	if resume {
+		if err := r.catchUp(ctx, stream); err != nil {
+			return err
+		}
+	}
+
+	refresh := time.NewTicker(authorsRefresh)
+	defer refresh.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-refresh.C:
+			added, err := r.refreshAuthors(ctx, stream, sub)
+			if err != nil {
+				return err
+			}
+			// Los tweets que los nuevos autores publicaron antes de suscribirse
+			if added {
+				if err := r.catchUp(ctx, stream); err != nil {
+					return err
+				}
+			}
+		case update := <-sub.updates:
+			// Los anuncios descartados se leen del timeline; incluyen los del buffer
+			if sub.lagged.Swap(false) {
+				if err := r.catchUp(ctx, stream); err != nil {
+					return err
+				}
+				continue
+			}
+			if update.Member == nil {
+				continue
+			}
+			if err := r.send(ctx, stream, []redis.Z{update}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// refreshAuthors ajusta la suscripción a los autores con muchos seguidores
+// que el usuario sigue, que cambian al seguir o dejar de seguir y cuando el
+// cron promueve o degrada autores. Indica si se añadió alguno.
+func (r *Repository) refreshAuthors(ctx context.Context, stream *timelineStream, sub *subscriber) (bool, error) {
+	celebrityIDs, err := r.followedCelebrities(ctx, stream.userID)
+	if err != nil {
+		return false, err
+	}
+
+	current := make(map[string]struct{}, len(celebrityIDs))
+	var added []string
+	for _, celebrityID := range celebrityIDs {
+		current[celebrityID] = struct{}{}
+		if _, ok := stream.authors[celebrityID]; !ok {
+			added = append(added, keys.AuthorChannel(celebrityID))
+		}
+	}
+
+	var removed []string
+	for authorID := range stream.authors {
+		if _, ok := current[authorID]; !ok {
+			removed = append(removed, keys.AuthorChannel(authorID))
+		}
+	}
+
+	if len(removed) > 0 {
+		r.hub.unsubscribe(sub, removed...)
+	}
+	if len(added) > 0 {
+		if err := r.hub.subscribe(ctx, sub, added...); err != nil {
+			return false, err
+		}
+	}
+	stream.authors = current
+
+	return len(added) > 0, nil
+}
+
+// catchUp envía los tweets del timeline, y de los autores seguidos que no se
+// distribuyen al escribir, posteriores al último enviado.
+func (r *Repository) catchUp(ctx context.Context, stream *timelineStream) error {
+	timelineKeys, err := r.timelineKeys(ctx, stream.userID)
+	if err != nil {
+		return err
+	}
+
+	for {
+		entries, more, err := r.rangeEntries(ctx, timelineKeys, stream.last, catchUpSize, true)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		// rangeEntries los devuelve del más reciente al más antiguo
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+		if err := r.send(ctx, stream, entries); err != nil {
+			return err
+		}
+
+		if !more {
+			return nil
+		}
+	}
+}
+
+// send hidrata los tweets y los envía en orden. Bloquea mientras el cliente no
+// los consume, por lo que los anuncios siguientes se acumulan en el buffer de
+// la conexión.
+func (r *Repository) send(ctx context.Context, stream *timelineStream, entries []redis.Z) error {
+	tweetIDs := make([]string, 0, len(entries))
+	scores := make(map[string]float64, len(entries))
+	for _, entry := range entries {
+		tweetID, _ := entry.Member.(string)
+		if _, ok := stream.sent[tweetID]; ok {
+			continue
+		}
+		tweetIDs = append(tweetIDs, tweetID)
+		scores[tweetID] = entry.Score
+	}
+	if len(tweetIDs) == 0 {
+		return nil
+	}
+
+	tweets, err := r.hydrate(ctx, tweetIDs)
+	if err != nil {
+		return err
+	}
+	hidden, err := r.hiddenAuthors(ctx, stream.userID)
+	if err != nil {
+		return err
+	}
+	tweets = filterAuthors(tweets, hidden)
+
+	for _, tweet := range tweets {
+		position := &cursor{score: scores[tweet.ID], id: tweet.ID}
+		select {
+		case stream.events <- &models.TimelineEvent{ID: position.encode(), Tweet: tweet}:
+		case <-ctx.Done():
+			return nil
+		}
+
+		if len(stream.sent) >= maxSentIDs {
+			stream.sent = make(map[string]struct{})
+		}
+		stream.sent[tweet.ID] = struct{}{}
+	}
+
+	// El cursor avanza también sobre los tweets omitidos para no volver a leerlos
+	for _, tweetID := range tweetIDs {
+		if score := scores[tweetID]; stream.last.greater(score, tweetID) {
+			stream.last = &cursor{score: score, id: tweetID}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+	"timeline-service/internal/domain/models"
+	"timeline-service/internal/infrastructure/keys"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// waitSubscribed espera a que Redis registre el número de suscripciones del
+// canal, ya que go-redis no espera la confirmación al suscribirse.
+func waitSubscribed(t *testing.T, server *miniredis.Miniredis, channel string, count int) {
+	assert.Eventually(t, func() bool {
+		return server.PubSubNumSub(channel)[channel] == count
+	}, time.Second, 5*time.Millisecond)
+}
+
+// publish anuncia el tweet como lo hace el cron.
+func publish(server *miniredis.Miniredis, channel string, score float64, tweetID string) {
+	server.Publish(channel, fmt.Sprintf("%d:%s", int64(score), tweetID))
+}
+
+// nextEvent devuelve el siguiente evento del stream o falla si no llega.
+func nextEvent(t *testing.T, events <-chan *models.TimelineEvent) *models.TimelineEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		if !assert.NotNil(t, event, "el stream se cerró") {
+			t.FailNow()
+		}
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("no llegó ningún evento")
+		return nil
+	}
+}
+
+// assertNoEvent comprueba que el stream no envía más eventos.
+func assertNoEvent(t *testing.T, events <-chan *models.TimelineEvent) {
+	t.Helper()
+	select {
+	case event := <-events:
+		t.Fatalf("evento inesperado: %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// nextUpdate devuelve el siguiente anuncio con tweet, saltando los avisos de
+// suscripción.
+func nextUpdate(t *testing.T, sub *subscriber) redis.Z {
+	t.Helper()
+	for {
+		select {
+		case update := <-sub.updates:
+			if update.Member != nil {
+				return update
+			}
+		case <-time.After(time.Second):
+			t.Fatal("no llegó ningún anuncio")
+			return redis.Z{}
+		}
+	}
+}
+
+func TestSubscriber_Notify(t *testing.T) {
+	sub := newSubscriber()
+
+	for i := 0; i < subscriberBuffer; i++ {
+		sub.notify(redis.Z{Score: float64(i), Member: fmt.Sprintf("t%d", i)}, false)
+	}
+	assert.False(t, sub.lagged.Load())
+
+	// Con el buffer lleno el anuncio se descarta sin bloquear al hub
+	sub.notify(redis.Z{Score: subscriberBuffer, Member: "extra"}, false)
+	assert.True(t, sub.lagged.Load())
+	assert.Len(t, sub.updates, subscriberBuffer)
+}
+
+func TestHub_Subscribe(t *testing.T) {
+	r, server := newTestRepository(t)
+	h := r.hub
+	channel := keys.TimelineChannel("u1")
+
+	first, second := newSubscriber(), newSubscriber()
+	assert.NoError(t, h.subscribe(context.Background(), first, channel))
+	assert.NoError(t, h.subscribe(context.Background(), second, channel))
+	// Las conexiones comparten la suscripción de Redis
+	waitSubscribed(t, server, channel, 1)
+
+	publish(server, channel, 1, "t1")
+	assert.Equal(t, "t1", nextUpdate(t, first).Member)
+	assert.Equal(t, "t1", nextUpdate(t, second).Member)
+
+	// Mientras quede una conexión el canal sigue suscrito
+	h.unsubscribe(first)
+	assert.Empty(t, first.channels)
+	publish(server, channel, 2, "t2")
+	assert.Equal(t, "t2", nextUpdate(t, second).Member)
+
+	h.unsubscribe(second)
+	waitSubscribed(t, server, channel, 0)
+	assert.Empty(t, h.subscribers)
+}
+
+func TestRepository_Stream_Celebrity(t *testing.T) {
+	r, server := newTestRepository(t)
+	_, err := server.SAdd("following:reader", "celeb")
+	assert.NoError(t, err)
+	_, err = server.SAdd(keys.Celebrities, "celeb")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := r.Stream(ctx, "reader", "")
+	assert.NoError(t, err)
+	waitSubscribed(t, server, keys.AuthorChannel("celeb"), 1)
+
+	// Los tweets de los autores con muchos seguidores solo se anuncian en su canal
+	score := addTweet(t, server, "t1", "celeb", time.Now().Add(time.Hour))
+	_, err = server.ZAdd("user_tweets:celeb", score, "t1")
+	assert.NoError(t, err)
+	publish(server, keys.AuthorChannel("celeb"), score, "t1")
+
+	assert.Equal(t, "t1", nextEvent(t, events).Tweet.ID)
+	assertNoEvent(t, events)
+}
+
+func TestRepository_Stream_CatchUp(t *testing.T) {
+	r, server := newTestRepository(t)
+	_, err := server.SAdd("following:reader", "author", "celeb")
+	assert.NoError(t, err)
+	_, err = server.SAdd(keys.Celebrities, "celeb")
+	assert.NoError(t, err)
+
+	base := time.Now().Add(-time.Hour)
+	seen := addTweet(t, server, "t0", "author", base)
+	first := addTweet(t, server, "t1", "author", base.Add(time.Minute))
+	second := addTweet(t, server, "t2", "celeb", base.Add(2*time.Minute))
+	_, err = server.ZAdd("timeline:reader", seen, "t0")
+	assert.NoError(t, err)
+	_, err = server.ZAdd("timeline:reader", first, "t1")
+	assert.NoError(t, err)
+	_, err = server.ZAdd("user_tweets:celeb", second, "t2")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lastEventID := cursor{score: seen, id: "t0"}.encode()
+	events, err := r.Stream(ctx, "reader", lastEventID)
+	assert.NoError(t, err)
+
+	// Se recuperan en orden los tweets del timeline y de los autores con
+	// muchos seguidores posteriores al último recibido
+	event := nextEvent(t, events)
+	assert.Equal(t, "t1", event.Tweet.ID)
+	assert.Equal(t, cursor{score: first, id: "t1"}.encode(), event.ID)
+	assert.Equal(t, "t2", nextEvent(t, events).Tweet.ID)
+
+	// Los anuncios de tweets ya enviados se descartan
+	waitSubscribed(t, server, keys.AuthorChannel("celeb"), 1)
+	publish(server, keys.TimelineChannel("reader"), first, "t1")
+	publish(server, keys.AuthorChannel("celeb"), second, "t2")
+	third := addTweet(t, server, "t3", "author", time.Now().Add(time.Hour))
+	publish(server, keys.TimelineChannel("reader"), third, "t3")
+
+	assert.Equal(t, "t3", nextEvent(t, events).Tweet.ID)
+	assertNoEvent(t, events)
+}
+
+func TestRepository_Stream_Lagged(t *testing.T) {
+	r, server := newTestRepository(t)
+	channel := keys.TimelineChannel("reader")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := r.Stream(ctx, "reader", "")
+	assert.NoError(t, err)
+	waitSubscribed(t, server, channel, 1)
+
+	// El cliente no lee mientras llegan más anuncios de los que caben en el buffer
+	total := subscriberBuffer * 2
+	base := time.Now().Add(time.Hour)
+	for i := 0; i < total; i++ {
+		tweetID := fmt.Sprintf("t%03d", i)
+		score := addTweet(t, server, tweetID, "author", base.Add(time.Duration(i)*time.Second))
+		_, err := server.ZAdd("timeline:reader", score, tweetID)
+		assert.NoError(t, err)
+		publish(server, channel, score, tweetID)
+	}
+
+	r.hub.mu.Lock()
+	var sub *subscriber
+	for s := range r.hub.subscribers[channel] {
+		sub = s
+	}
+	r.hub.mu.Unlock()
+	// El stream queda bloqueado enviando el primer tweet y el buffer se llena,
+	// así que los anuncios siguientes se descartan
+	assert.Eventually(t, func() bool {
+		return len(sub.updates) >= subscriberBuffer-1
+	}, time.Second, 5*time.Millisecond)
+
+	// Los anuncios descartados se recuperan del timeline, sin repetir ni saltar tweets
+	for i := 0; i < total; i++ {
+		assert.Equal(t, fmt.Sprintf("t%03d", i), nextEvent(t, events).Tweet.ID)
+	}
+	assertNoEvent(t, events)
+}
+
+func TestRepository_RefreshAuthors(t *testing.T) {
+	r, server := newTestRepository(t)
+	_, err := server.SAdd(keys.Celebrities, "celeb", "other")
+	assert.NoError(t, err)
+	_, err = server.SAdd("following:reader", "celeb")
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	sub := newSubscriber()
+	stream := &timelineStream{userID: "reader", authors: map[string]struct{}{"celeb": {}}}
+	assert.NoError(t, r.hub.subscribe(ctx, sub, keys.AuthorChannel("celeb")))
+
+	// El usuario deja de seguir a un autor y sigue a otro
+	_, err = server.SRem("following:reader", "celeb")
+	assert.NoError(t, err)
+	_, err = server.SAdd("following:reader", "other")
+	assert.NoError(t, err)
+
+	added, err := r.refreshAuthors(ctx, stream, sub)
+	assert.NoError(t, err)
+	assert.True(t, added)
+	assert.Equal(t, map[string]struct{}{"other": {}}, stream.authors)
+	assert.Equal(t, map[string]struct{}{keys.AuthorChannel("other"): {}}, sub.channels)
+	waitSubscribed(t, server, keys.AuthorChannel("celeb"), 0)
+	waitSubscribed(t, server, keys.AuthorChannel("other"), 1)
+
+	added, err = r.refreshAuthors(ctx, stream, sub)
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	r.hub.unsubscribe(sub)
+}
@@ -2,4 +2,5 @@ package interfaces
 
 type Cron interface {
 	ProcessTweets()
+	ProcessFollows()
 }
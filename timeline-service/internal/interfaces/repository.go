@@ -6,5 +6,10 @@ import (
 )
 
 type Repository interface {
-	Paginate(ctx context.Context, id string, page, size int) ([]*models.Timeline, error)
+	Paginate(ctx context.Context, id string, query *models.TimelineQuery) (*models.TimelinePage, error)
+	Stream(ctx context.Context, id, lastEventID string) (<-chan *models.TimelineEvent, error)
+	DeadLetters(ctx context.Context, page, size int) ([]*models.DeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, tweetID string) error
+	DiscardDeadLetter(ctx context.Context, tweetID string) error
+	MigrateTimelines(ctx context.Context)
 }
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"context"
+	"log"
+	"shared/auth"
 	"tweet-service/config"
 	"tweet-service/internal/application"
 	"tweet-service/internal/infrastructure/http"
@@ -19,6 +22,10 @@ func main() {
 	sqlite := cfg.Sqlite()
 	redis := cfg.Redis()
 
+	if err := repository.MigrateSearch(sqlite); err != nil {
+		log.Fatalf("Error al migrar el índice de búsqueda: %v", err)
+	}
+
 	// Inicializar repositorio
 	repo := repository.NewRepository(sqlite, redis)
 
@@ -29,8 +36,18 @@ func main() {
 		seed.Seed()
 	}
 
+	// Completar en Redis el índice por autor de los tweets creados antes de existir
+	if err := repo.SyncCache(context.Background()); err != nil {
+		log.Printf("Error al sincronizar los tweets en Redis: %v", err)
+	}
+
 	service := application.NewService(repo)
 
-	httpServer := http.NewHTTPServer(engine, service, validate)
+	verifier, err := auth.NewVerifier(cfg.Auth.Config, redis)
+	if err != nil {
+		log.Fatalf("Error al configurar la autenticación: %v", err)
+	}
+
+	httpServer := http.NewHTTPServer(engine, service, validate, cfg.Admins, auth.Middleware(verifier, cfg.Auth.LegacyHeader))
 	httpServer.Run(cfg.Port)
 }
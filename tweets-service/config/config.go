@@ -3,6 +3,9 @@ package config
 import (
 	"context"
 	"log"
+	"os"
+	"shared/auth"
+	"strings"
 	"tweet-service/internal/domain/models"
 
 	"github.com/glebarez/sqlite"
@@ -16,6 +19,18 @@ type Config struct {
 	SqlitePath   string
 	Env          string
 	RedisOptions *redis.Options
+	// Admins son los usuarios que pueden eliminar tweets ajenos
+	Admins []string
+	Auth   AuthConfig
+}
+
+// AuthConfig controla la verificación de los JWT que emite el user-service.
+// Con HS256 se usa Secret; con EdDSA, la clave pública Ed25519 en formato PEM.
+// Ambas se leen de las variables AUTH_SECRET y AUTH_PUBLIC_KEY.
+type AuthConfig struct {
+	auth.Config
+	// LegacyHeader acepta el header User-ID sin token, solo para desarrollo local
+	LegacyHeader bool
 }
 
 func LoadConfig() *Config {
@@ -23,6 +38,12 @@ func LoadConfig() *Config {
 	viper.SetConfigType("yml")
 	viper.AddConfigPath(".")
 
+	viper.SetDefault("auth.algorithm", "HS256")
+	viper.SetDefault("auth.issuer", "user-service")
+	viper.SetDefault("auth.legacy_header", false)
+	viper.BindEnv("auth.secret", "AUTH_SECRET")
+	viper.BindEnv("auth.public_key", "AUTH_PUBLIC_KEY")
+
 	if err := viper.ReadInConfig(); err != nil {
 		log.Fatalf("Error al leer la configuración: %v", err)
 	}
@@ -36,7 +57,34 @@ func LoadConfig() *Config {
 			Password: viper.GetString("db.redis.password"),
 			DB:       viper.GetInt("db.redis.db"),
 		},
+		Admins: admins(),
+		Auth: AuthConfig{
+			Config: auth.Config{
+				Algorithm: viper.GetString("auth.algorithm"),
+				Secret:    viper.GetString("auth.secret"),
+				PublicKey: viper.GetString("auth.public_key"),
+				Issuer:    viper.GetString("auth.issuer"),
+			},
+			LegacyHeader: viper.GetBool("auth.legacy_header"),
+		},
+	}
+}
+
+// admins devuelve los administradores de la variable de entorno ADMINS,
+// separados por comas, o en su defecto los de la clave admins.
+func admins() []string {
+	env := os.Getenv("ADMINS")
+	if env == "" {
+		return viper.GetStringSlice("admins")
+	}
+
+	var ids []string
+	for _, id := range strings.Split(env, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
 	}
+	return ids
 }
 
 func (c *Config) Sqlite() *gorm.DB {
@@ -46,7 +94,7 @@ func (c *Config) Sqlite() *gorm.DB {
 	}
 
 	// Migrar los modelos para crear tablas automáticamente
-	if err := db.AutoMigrate(&models.Tweet{}, &models.Tag{}, &models.Comment{}); err != nil {
+	if err := db.AutoMigrate(&models.Tweet{}, &models.Tag{}, &models.Comment{}, &models.TweetLike{}, &models.TweetDeletion{}, &models.Mention{}); err != nil {
 		log.Fatalf("Error al migrar las tablas: %v", err)
 	}
 	db.Exec("PRAGMA foreign_keys = ON;")
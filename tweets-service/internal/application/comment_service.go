@@ -0,0 +1,50 @@
+package application
+
+import (
+	"context"
+	"time"
+	"tweet-service/internal/application/dto"
+
+	"github.com/jinzhu/copier"
+)
+
+func (s *tweetservice) CreateComment(ctx context.Context, tweetID, userID string, comment *dto.CreateComment) (*dto.Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	newComment, err := s.repo.CreateComment(ctx, tweetID, userID, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	commentDTO := &dto.Comment{}
+	if err := copier.Copy(commentDTO, newComment); err != nil {
+		return nil, err
+	}
+
+	return commentDTO, nil
+}
+
+func (s *tweetservice) Comments(ctx context.Context, tweetID, viewerID string, query *dto.CommentQuery) (*dto.CommentPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	comments, nextCursor, err := s.repo.Comments(ctx, tweetID, viewerID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.CommentPage{Comments: []dto.Comment{}, NextCursor: nextCursor}
+	if err := copier.Copy(&page.Comments, comments); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (s *tweetservice) DeleteComment(ctx context.Context, id, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	return s.repo.DeleteComment(ctx, id, userID)
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type Comment struct {
+	ID        string    `json:"id"`
+	TweetID   string    `json:"tweetId"`
+	ParentID  *string   `json:"parentId,omitempty"`
+	UserID    string    `json:"userId"`
+	Content   string    `json:"content"`
+	Replies   int       `json:"replies"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateComment struct {
+	ParentID *string `json:"parentId" validate:"omitempty,uuid"`
+	Content  string  `json:"content" validate:"required,min=1,max=280"`
+}
+
+type CommentQuery struct {
+	ParentID string `form:"parentId" validate:"omitempty,uuid"`
+	Cursor   string `form:"cursor"`
+	Size     int    `form:"size" validate:"omitempty,min=1,max=100"`
+	Order    string `form:"order" validate:"omitempty,oneof=asc desc"`
+}
+
+type CommentPage struct {
+	Comments   []Comment `json:"comments"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+}
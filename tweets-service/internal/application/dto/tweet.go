@@ -1,13 +1,79 @@
 package dto
 
+import "time"
+
 type Tweet struct {
-	ID      string `json:"id"`
-	UserID  string `json:"userId" validate:"required,uuid"`
-	Content string `json:"content" validate:"required,min=1,max=280"`
+	ID          string  `json:"id"`
+	UserID      string  `json:"userId" validate:"required,uuid"`
+	Kind        string  `json:"kind"`
+	ReferenceID *string `json:"referenceId,omitempty"`
+	Content     string  `json:"content" validate:"required,min=1,max=280"`
+
+	Likes         int       `json:"likes"`
+	Shares        int       `json:"shares"`
+	CountComments int       `json:"comments"`
+	CreatedAt     time.Time `json:"createdAt"`
+
+	Entities Entities `json:"entities"`
+}
+
+// Entities ubica en el contenido los hashtags, menciones y URLs. Start y End
+// son posiciones en caracteres, con End exclusivo.
+type Entities struct {
+	Hashtags []Entity  `json:"hashtags"`
+	Mentions []Mention `json:"mentions"`
+	URLs     []Entity  `json:"urls"`
+}
+
+type Entity struct {
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+type Mention struct {
+	UserID   string `json:"userId"`
+	Nickname string `json:"nickname"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
 }
 
 type CreateTweet struct {
-	UserID  string   `json:"userId" validate:"required,uuid"`
+	// El autor es siempre el usuario autenticado
+	UserID  string   `json:"-" validate:"required,uuid"`
 	Content string   `json:"content" validate:"required,min=1,max=280"`
 	Tags    []string `json:"tags" validate:"max=5,dive,min=5,max=20"`
 }
+
+type CreateQuote struct {
+	Content string `json:"content" validate:"required,min=1,max=280"`
+}
+
+type TweetQuery struct {
+	Cursor string `form:"cursor"`
+	Size   int    `form:"size" validate:"omitempty,min=1,max=100"`
+}
+
+type TweetPage struct {
+	Tweets     []Tweet `json:"tweets"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
+
+// SearchQuery admite en Q palabras, "frases", #tags y los operadores
+// from:nickname, since:AAAA-MM-DD y until:AAAA-MM-DD.
+type SearchQuery struct {
+	Q      string `form:"q" validate:"required,max=200"`
+	Order  string `form:"order" validate:"omitempty,oneof=relevance recent"`
+	Cursor string `form:"cursor"`
+	Size   int    `form:"size" validate:"omitempty,min=1,max=100"`
+}
+
+type TrendingQuery struct {
+	Window string `form:"window" validate:"omitempty,oneof=hour day"`
+	Size   int    `form:"size" validate:"omitempty,min=1,max=50"`
+}
+
+type TrendingTag struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
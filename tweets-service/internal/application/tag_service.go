@@ -0,0 +1,46 @@
+package application
+
+import (
+	"context"
+	"time"
+	"tweet-service/internal/application/dto"
+
+	"github.com/jinzhu/copier"
+)
+
+func (s *tweetservice) TagTweets(ctx context.Context, name, viewerID string, query *dto.TweetQuery) (*dto.TweetPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	tweets, nextCursor, err := s.repo.TagTweets(ctx, name, viewerID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.TweetPage{Tweets: []dto.Tweet{}, NextCursor: nextCursor}
+	if err := copier.Copy(&page.Tweets, tweets); err != nil {
+		return nil, err
+	}
+	for i, tweet := range tweets {
+		setEntities(&page.Tweets[i], tweet)
+	}
+
+	return page, nil
+}
+
+func (s *tweetservice) Trending(ctx context.Context, query *dto.TrendingQuery) ([]dto.TrendingTag, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	tags, err := s.repo.Trending(ctx, query.Window, query.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	trending := []dto.TrendingTag{}
+	if err := copier.Copy(&trending, tags); err != nil {
+		return nil, err
+	}
+
+	return trending, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 	"tweet-service/internal/application/dto"
+	"tweet-service/internal/domain/entities"
+	"tweet-service/internal/domain/models"
 	"tweet-service/internal/interfaces"
 
 	"github.com/jinzhu/copier"
@@ -32,18 +34,175 @@ func (s *tweetservice) Create(ctx context.Context, tweet *dto.CreateTweet) (*dto
 	if err := copier.Copy(tweetDTO, newtweet); err != nil {
 		return nil, err
 	}
+	setEntities(tweetDTO, newtweet)
 
 	return tweetDTO, nil
 }
 
-func (s *tweetservice) Delete(ctx context.Context, id string) error {
+func (s *tweetservice) Get(ctx context.Context, id, userID string) (*dto.Tweet, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
-	err := s.repo.Delete(ctx, id)
+	tweet, err := s.repo.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tweetDTO := &dto.Tweet{}
+	if err := copier.Copy(tweetDTO, tweet); err != nil {
+		return nil, err
+	}
+	setEntities(tweetDTO, tweet)
+
+	return tweetDTO, nil
+}
+
+func (s *tweetservice) UserTweets(ctx context.Context, userID, viewerID string, query *dto.TweetQuery) (*dto.TweetPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	tweets, nextCursor, err := s.repo.UserTweets(ctx, userID, viewerID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.TweetPage{Tweets: []dto.Tweet{}, NextCursor: nextCursor}
+	if err := copier.Copy(&page.Tweets, tweets); err != nil {
+		return nil, err
+	}
+	for i, tweet := range tweets {
+		setEntities(&page.Tweets[i], tweet)
+	}
+
+	return page, nil
+}
+
+func (s *tweetservice) Search(ctx context.Context, query *dto.SearchQuery, viewerID string) (*dto.TweetPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	tweets, nextCursor, err := s.repo.Search(ctx, query, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.TweetPage{Tweets: []dto.Tweet{}, NextCursor: nextCursor}
+	if err := copier.Copy(&page.Tweets, tweets); err != nil {
+		return nil, err
+	}
+	for i, tweet := range tweets {
+		setEntities(&page.Tweets[i], tweet)
+	}
+
+	return page, nil
+}
+
+func (s *tweetservice) Delete(ctx context.Context, id, userID string, admin bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	err := s.repo.Delete(ctx, id, userID, admin)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (s *tweetservice) Retweet(ctx context.Context, id, userID string) (*dto.Tweet, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	tweet, err := s.repo.Retweet(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tweetDTO := &dto.Tweet{}
+	if err := copier.Copy(tweetDTO, tweet); err != nil {
+		return nil, err
+	}
+	setEntities(tweetDTO, tweet)
+
+	return tweetDTO, nil
+}
+
+func (s *tweetservice) Quote(ctx context.Context, id, userID string, quote *dto.CreateQuote) (*dto.Tweet, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	tweet, err := s.repo.Quote(ctx, id, userID, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	tweetDTO := &dto.Tweet{}
+	if err := copier.Copy(tweetDTO, tweet); err != nil {
+		return nil, err
+	}
+	setEntities(tweetDTO, tweet)
+
+	return tweetDTO, nil
+}
+
+func (s *tweetservice) Like(ctx context.Context, id, userID string) (*dto.Tweet, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	tweet, err := s.repo.Like(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tweetDTO := &dto.Tweet{}
+	if err := copier.Copy(tweetDTO, tweet); err != nil {
+		return nil, err
+	}
+	setEntities(tweetDTO, tweet)
+
+	return tweetDTO, nil
+}
+
+func (s *tweetservice) Unlike(ctx context.Context, id, userID string) (*dto.Tweet, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	tweet, err := s.repo.Unlike(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tweetDTO := &dto.Tweet{}
+	if err := copier.Copy(tweetDTO, tweet); err != nil {
+		return nil, err
+	}
+	setEntities(tweetDTO, tweet)
+
+	return tweetDTO, nil
+}
+
+// setEntities completa las entidades del tweet: los hashtags y las URLs se
+// extraen del contenido y las menciones son las resueltas al publicarlo.
+func setEntities(tweetDTO *dto.Tweet, tweet *models.Tweet) {
+	parsed := entities.Parse(tweet.Content)
+
+	tweetDTO.Entities = dto.Entities{
+		Hashtags: []dto.Entity{},
+		Mentions: []dto.Mention{},
+		URLs:     []dto.Entity{},
+	}
+	for _, hashtag := range parsed.Hashtags {
+		tweetDTO.Entities.Hashtags = append(tweetDTO.Entities.Hashtags, dto.Entity(hashtag))
+	}
+	for _, url := range parsed.URLs {
+		tweetDTO.Entities.URLs = append(tweetDTO.Entities.URLs, dto.Entity(url))
+	}
+	for _, mention := range tweet.Mentions {
+		tweetDTO.Entities.Mentions = append(tweetDTO.Entities.Mentions, dto.Mention{
+			UserID:   mention.UserID,
+			Nickname: mention.Nickname,
+			Start:    mention.Start,
+			End:      mention.End,
+		})
+	}
+}
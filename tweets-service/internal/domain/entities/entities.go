@@ -0,0 +1,109 @@
+// Package entities extrae hashtags, menciones y URLs del contenido de un
+// tweet. Las posiciones se expresan en caracteres (runas), no en bytes, para
+// que los clientes puedan resaltarlas sin conocer la codificación.
+package entities
+
+import (
+	"strings"
+	"unicode"
+)
+
+type Entity struct {
+	// Text es el hashtag sin #, el nickname sin @ o la URL completa
+	Text  string
+	Start int
+	End   int
+}
+
+type Entities struct {
+	Hashtags []Entity
+	Mentions []Entity
+	URLs     []Entity
+}
+
+// urlTrailing son los signos de puntuación que suelen cerrar una frase y no
+// forman parte de la URL.
+const urlTrailing = ".,;:!?)]}'\""
+
+// Parse recorre content y devuelve sus entidades en orden de aparición. Las
+// URLs se detectan primero para no confundir sus fragmentos con hashtags.
+func Parse(content string) Entities {
+	runes := []rune(content)
+	var result Entities
+
+	for i := 0; i < len(runes); {
+		if end := urlEnd(runes, i); end > i {
+			result.URLs = append(result.URLs, Entity{Text: string(runes[i:end]), Start: i, End: end})
+			i = end
+			continue
+		}
+
+		r := runes[i]
+		if (r == '#' || r == '@') && (i == 0 || !isWordRune(runes[i-1])) {
+			end := i + 1
+			for end < len(runes) && isWordRune(runes[end]) {
+				end++
+			}
+			text := string(runes[i+1 : end])
+			entity := Entity{Text: text, Start: i, End: end}
+			if r == '@' && text != "" {
+				result.Mentions = append(result.Mentions, entity)
+			}
+			// Un hashtag necesita al menos una letra, como #2024a pero no #2024
+			if r == '#' && text != "" && !isNumeric(text) {
+				result.Hashtags = append(result.Hashtags, entity)
+			}
+			i = end
+			continue
+		}
+
+		i++
+	}
+
+	return result
+}
+
+// urlEnd devuelve la posición donde termina la URL que empieza en start, o
+// start si no empieza ninguna.
+func urlEnd(runes []rune, start int) int {
+	if start > 0 && !unicode.IsSpace(runes[start-1]) && !strings.ContainsRune("([{\"'", runes[start-1]) {
+		return start
+	}
+
+	rest := string(runes[start:min(start+8, len(runes))])
+	var scheme int
+	switch {
+	case strings.HasPrefix(strings.ToLower(rest), "https://"):
+		scheme = 8
+	case strings.HasPrefix(strings.ToLower(rest), "http://"):
+		scheme = 7
+	default:
+		return start
+	}
+
+	end := start + scheme
+	for end < len(runes) && !unicode.IsSpace(runes[end]) {
+		end++
+	}
+	for end > start+scheme && strings.ContainsRune(urlTrailing, runes[end-1]) {
+		end--
+	}
+	if end == start+scheme {
+		return start
+	}
+
+	return end
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+func isNumeric(text string) bool {
+	for _, r := range text {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	content := "Hola @mary, mira #Café y #2024 en https://example.com/a#b."
+
+	result := Parse(content)
+
+	assert.Equal(t, []Entity{{Text: "mary", Start: 5, End: 10}}, result.Mentions)
+	assert.Equal(t, []Entity{{Text: "Café", Start: 17, End: 22}}, result.Hashtags)
+	assert.Equal(t, []Entity{{Text: "https://example.com/a#b", Start: 34, End: 57}}, result.URLs)
+}
+
+func TestParse_IgnoresEmbeddedSymbols(t *testing.T) {
+	result := Parse("correo@example.com y C# no son entidades, # tampoco")
+
+	assert.Empty(t, result.Mentions)
+	assert.Empty(t, result.Hashtags)
+	assert.Empty(t, result.URLs)
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TweetDeletion registra la eliminación de un tweet por un administrador que
+// no es su autor.
+type TweetDeletion struct {
+	ID        string    `gorm:"type:uuid;primaryKey"`
+	TweetID   string    `gorm:"type:uuid;index;not null"`
+	AuthorID  string    `gorm:"type:uuid;index;not null"`
+	AdminID   string    `gorm:"type:uuid;index;not null"`
+	Content   string    `gorm:"size:280;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (deletion *TweetDeletion) BeforeCreate(tx *gorm.DB) (err error) {
+	if deletion.ID == "" {
+		deletion.ID = uuid.New().String()
+	}
+	return
+}
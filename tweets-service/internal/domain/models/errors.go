@@ -0,0 +1,17 @@
+package models
+
+import "errors"
+
+var (
+	ErrTweetNotFound   = errors.New("tweet no encontrado")
+	ErrCommentNotFound = errors.New("comentario no encontrado")
+	ErrForbidden       = errors.New("no tienes permiso para realizar esta acción")
+	ErrInvalidCursor   = errors.New("cursor inválido")
+	ErrAlreadyShared   = errors.New("el usuario ya compartió este tweet")
+	ErrBlocked         = errors.New("existe un bloqueo entre los usuarios")
+	ErrPrivateAccount  = errors.New("la cuenta es privada")
+	ErrInvalidWindow   = errors.New("ventana de tendencias inválida")
+	ErrTooManyTags     = errors.New("un tweet no puede tener más de 5 tags")
+	ErrTagTooLong      = errors.New("un hashtag no puede superar los 20 caracteres")
+	ErrInvalidQuery    = errors.New("consulta de búsqueda inválida")
+)
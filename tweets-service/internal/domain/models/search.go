@@ -0,0 +1,6 @@
+package models
+
+const (
+	SearchOrderRelevance = "relevance"
+	SearchOrderRecent    = "recent"
+)
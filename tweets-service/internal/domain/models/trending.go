@@ -0,0 +1,13 @@
+package models
+
+const (
+	TrendingWindowHour = "hour"
+	TrendingWindowDay  = "day"
+)
+
+// TrendingTag es el número de tweets publicados con un tag dentro de una
+// ventana de tendencias.
+type TrendingTag struct {
+	Name  string
+	Count int64
+}
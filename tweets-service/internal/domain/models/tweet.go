@@ -7,11 +7,20 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	TweetKindOriginal = "tweet"
+	TweetKindRetweet  = "retweet"
+	TweetKindQuote    = "quote"
+)
+
 type Tweet struct {
 	ID            string         `gorm:"type:uuid;primaryKey"`
 	UserID        string         `gorm:"type:uuid;index;not null"`
+	Kind          string         `gorm:"size:10;not null;default:tweet"`
+	ReferenceID   *string        `gorm:"type:uuid;index"`
 	Content       string         `gorm:"size:280;not null"`
 	Tags          []Tag          `gorm:"many2many:tweet_tags"`
+	Mentions      []Mention      `gorm:"foreignKey:TweetID"`
 	Comments      []Comment      `gorm:"foreignKey:TweetID"`
 	CountComments int            `gorm:"type:int;not null;default:0"`
 	Likes         int            `gorm:"type:int;not null;default:0"`
@@ -41,11 +50,31 @@ func (tag *Tag) BeforeCreate(tx *gorm.DB) (err error) {
 	return
 }
 
+// Mention es una mención @nickname resuelta a un usuario, con su posición en
+// caracteres dentro del contenido del tweet.
+type Mention struct {
+	ID       string `gorm:"type:uuid;primaryKey"`
+	TweetID  string `gorm:"type:uuid;index;not null"`
+	UserID   string `gorm:"type:uuid;index;not null"`
+	Nickname string `gorm:"size:50;not null"`
+	Start    int    `gorm:"not null"`
+	End      int    `gorm:"not null"`
+}
+
+func (mention *Mention) BeforeCreate(tx *gorm.DB) (err error) {
+	if mention.ID == "" {
+		mention.ID = uuid.New().String()
+	}
+	return
+}
+
 type Comment struct {
 	ID        string         `gorm:"type:uuid;primaryKey"`
 	UserID    string         `gorm:"type:uuid;index;not null"`
 	TweetID   string         `gorm:"type:uuid;index;not null"`
+	ParentID  *string        `gorm:"type:uuid;index"`
 	Content   string         `gorm:"size:280;not null"`
+	Replies   int            `gorm:"type:int;not null;default:0"`
 	Likes     int            `gorm:"type:int;not null,default:0"`
 	Shares    int            `gorm:"type:int;not null,default:0"`
 	CreatedAt time.Time      `gorm:"autoCreateTime"`
@@ -58,3 +87,17 @@ func (comment *Comment) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return
 }
+
+type TweetLike struct {
+	ID        string    `gorm:"type:uuid;primaryKey"`
+	UserID    string    `gorm:"type:uuid;not null;uniqueIndex:idx_tweet_like_user"`
+	TweetID   string    `gorm:"type:uuid;not null;uniqueIndex:idx_tweet_like_user;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (like *TweetLike) BeforeCreate(tx *gorm.DB) (err error) {
+	if like.ID == "" {
+		like.ID = uuid.New().String()
+	}
+	return
+}
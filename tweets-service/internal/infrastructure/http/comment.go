@@ -0,0 +1,63 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"shared/auth"
+	"tweet-service/internal/application/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (s *HTTPServer) createComment(c *gin.Context) {
+	var comment dto.CreateComment
+
+	if err := c.ShouldBindJSON(&comment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(comment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	createdComment, err := s.tweetservice.CreateComment(c.Request.Context(), c.Param("id"), c.GetString(auth.UserIDKey), &comment)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, createdComment)
+}
+
+func (s *HTTPServer) comments(c *gin.Context) {
+	var query dto.CommentQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	page, err := s.tweetservice.Comments(c.Request.Context(), c.Param("id"), c.GetString(auth.UserIDKey), &query)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (s *HTTPServer) deleteComment(c *gin.Context) {
+	if err := s.tweetservice.DeleteComment(c.Request.Context(), c.Param("id"), c.GetString(auth.UserIDKey)); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comentario eliminado correctamente"})
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"shared/auth"
+	"tweet-service/internal/application/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (s *HTTPServer) tagTweets(c *gin.Context) {
+	var query dto.TweetQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	page, err := s.tweetservice.TagTweets(c.Request.Context(), c.Param("name"), c.GetString(auth.UserIDKey), &query)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (s *HTTPServer) trending(c *gin.Context) {
+	var query dto.TrendingQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	tags, err := s.tweetservice.Trending(c.Request.Context(), &query)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
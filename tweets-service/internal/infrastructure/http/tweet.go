@@ -1,9 +1,12 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"shared/auth"
 	"tweet-service/internal/application/dto"
+	"tweet-service/internal/domain/models"
 	"tweet-service/internal/interfaces"
 
 	"github.com/gin-gonic/gin"
@@ -14,13 +17,20 @@ type HTTPServer struct {
 	engine       *gin.Engine
 	validate     *validator.Validate
 	tweetservice interfaces.Tweetservice
+	admins       map[string]struct{}
+	auth         gin.HandlerFunc
 }
 
-func NewHTTPServer(engine *gin.Engine, tweetservice interfaces.Tweetservice, validate *validator.Validate) *HTTPServer {
+func NewHTTPServer(engine *gin.Engine, tweetservice interfaces.Tweetservice, validate *validator.Validate, admins []string, auth gin.HandlerFunc) *HTTPServer {
 	server := &HTTPServer{
 		engine:       engine,
 		validate:     validate,
 		tweetservice: tweetservice,
+		admins:       make(map[string]struct{}, len(admins)),
+		auth:         auth,
+	}
+	for _, admin := range admins {
+		server.admins[admin] = struct{}{}
 	}
 	server.registerRoutes()
 	return server
@@ -34,11 +44,22 @@ func (s *HTTPServer) Run(port string) {
 
 func (s *HTTPServer) registerRoutes() {
 
-	authorized := s.engine.Group("/", AuthMiddleware())
+	authorized := s.engine.Group("/", s.auth)
 	{
 		authorized.POST("/tweets", s.create)
+		authorized.GET("/tweets/:id", s.get)
+		authorized.GET("/users/:id/tweets", s.userTweets)
 		authorized.DELETE("/tweets/:id", s.delete)
-
+		authorized.POST("/tweets/:id/retweet", s.retweet)
+		authorized.POST("/tweets/:id/quote", s.quote)
+		authorized.POST("/tweets/:id/like", s.like)
+		authorized.DELETE("/tweets/:id/like", s.unlike)
+		authorized.POST("/tweets/:id/comments", s.createComment)
+		authorized.GET("/tweets/:id/comments", s.comments)
+		authorized.DELETE("/comments/:id", s.deleteComment)
+		authorized.GET("/tags/trending", s.trending)
+		authorized.GET("/search/tweets", s.search)
+		authorized.GET("/tags/:name/tweets", s.tagTweets)
 	}
 }
 
@@ -49,6 +70,7 @@ func (s *HTTPServer) create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	tweet.UserID = c.GetString(auth.UserIDKey)
 
 	if err := s.validate.Struct(tweet); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
@@ -57,20 +79,154 @@ func (s *HTTPServer) create(c *gin.Context) {
 
 	createdtweet, err := s.tweetservice.Create(c.Request.Context(), &tweet)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusCreated, createdtweet)
 }
 
+func (s *HTTPServer) get(c *gin.Context) {
+	tweet, err := s.tweetservice.Get(c.Request.Context(), c.Param("id"), c.GetString(auth.UserIDKey))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tweet)
+}
+
+func (s *HTTPServer) userTweets(c *gin.Context) {
+	var query dto.TweetQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	page, err := s.tweetservice.UserTweets(c.Request.Context(), c.Param("id"), c.GetString(auth.UserIDKey), &query)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (s *HTTPServer) search(c *gin.Context) {
+	var query dto.SearchQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	page, err := s.tweetservice.Search(c.Request.Context(), &query, c.GetString(auth.UserIDKey))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
 func (s *HTTPServer) delete(c *gin.Context) {
 	id := c.Param("id")
+	userID := c.GetString(auth.UserIDKey)
+	_, admin := s.admins[userID]
 
-	if err := s.tweetservice.Delete(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := s.tweetservice.Delete(c.Request.Context(), id, userID, admin); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Tweet eliminado correctamente"})
 }
+
+func (s *HTTPServer) retweet(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString(auth.UserIDKey)
+
+	tweet, err := s.tweetservice.Retweet(c.Request.Context(), id, userID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tweet)
+}
+
+func (s *HTTPServer) quote(c *gin.Context) {
+	var quote dto.CreateQuote
+
+	if err := c.ShouldBindJSON(&quote); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(quote); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	tweet, err := s.tweetservice.Quote(c.Request.Context(), c.Param("id"), c.GetString(auth.UserIDKey), &quote)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tweet)
+}
+
+func (s *HTTPServer) like(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString(auth.UserIDKey)
+
+	tweet, err := s.tweetservice.Like(c.Request.Context(), id, userID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tweet)
+}
+
+func (s *HTTPServer) unlike(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString(auth.UserIDKey)
+
+	tweet, err := s.tweetservice.Unlike(c.Request.Context(), id, userID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tweet)
+}
+
+// errorStatus traduce los errores de dominio al código HTTP correspondiente.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrTweetNotFound), errors.Is(err, models.ErrCommentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrForbidden), errors.Is(err, models.ErrBlocked), errors.Is(err, models.ErrPrivateAccount):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrInvalidCursor), errors.Is(err, models.ErrInvalidWindow), errors.Is(err, models.ErrTooManyTags),
+		errors.Is(err, models.ErrTagTooLong), errors.Is(err, models.ErrInvalidQuery):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrAlreadyShared):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Cada interacción de un usuario con los tweets de un autor suma a
+// affinity:<usuario> un peso según su tipo. El timeline-service lo usa para
+// ordenar el timeline por relevancia.
+const (
+	likeAffinity    = 1
+	commentAffinity = 2
+	shareAffinity   = 3
+	// affinityTTL descarta la afinidad de los usuarios que dejan de interactuar
+	affinityTTL = 30 * 24 * time.Hour
+)
+
+func affinityKey(userID string) string {
+	return fmt.Sprintf("affinity:%s", userID)
+}
+
+// recordAffinity ajusta la afinidad de userID con authorID. Las interacciones
+// con los tweets propios no cuentan. Un fallo no afecta a la interacción, que
+// ya se guardó, por lo que solo se registra.
+func (r *repository) recordAffinity(ctx context.Context, userID, authorID string, weight float64) {
+	if userID == authorID {
+		return
+	}
+
+	key := affinityKey(userID)
+	pipe := r.redis.TxPipeline()
+	pipe.ZIncrBy(ctx, key, weight, authorID)
+	pipe.Expire(ctx, key, affinityTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Error al registrar la afinidad de %s con %s: %v", userID, authorID, err)
+	}
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tweet-service/internal/application/dto"
+	"tweet-service/internal/domain/models"
+
+	"gorm.io/gorm"
+)
+
+const defaultPageSize = 20
+
+func (r *repository) CreateComment(ctx context.Context, tweetID, userID string, createComment *dto.CreateComment) (*models.Comment, error) {
+	comment := &models.Comment{
+		TweetID:  tweetID,
+		UserID:   userID,
+		ParentID: createComment.ParentID,
+		Content:  createComment.Content,
+	}
+	tweet := &models.Tweet{}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(tweet, "id = ?", tweetID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrTweetNotFound
+			}
+			return fmt.Errorf("error al obtener el tweet: %w", err)
+		}
+
+		// No se puede responder a un usuario con el que existe un bloqueo ni a
+		// una cuenta privada que no se sigue
+		if err := r.checkBlocked(ctx, userID, tweet.UserID); err != nil {
+			return err
+		}
+		if err := r.checkVisible(ctx, tweet.UserID, userID); err != nil {
+			return err
+		}
+
+		// Una respuesta solo puede colgar de un comentario del mismo tweet
+		if comment.ParentID != nil {
+			parent := &models.Comment{}
+			if err := tx.First(parent, "id = ? AND tweet_id = ?", *comment.ParentID, tweetID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return models.ErrCommentNotFound
+				}
+				return fmt.Errorf("error al obtener el comentario padre: %w", err)
+			}
+
+			if err := r.checkBlocked(ctx, userID, parent.UserID); err != nil {
+				return err
+			}
+
+			if err := tx.Model(parent).UpdateColumn("replies", gorm.Expr("replies + ?", 1)).Error; err != nil {
+				return fmt.Errorf("error al incrementar las respuestas: %w", err)
+			}
+		}
+
+		if err := tx.Create(comment).Error; err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+			}
+			return fmt.Errorf("error al crear el comentario: %w", err)
+		}
+
+		return r.updateCounter(tx, tweet, "count_comments", 1)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.setTweet(ctx, tweet); err != nil {
+		return nil, err
+	}
+	r.recordAffinity(ctx, userID, tweet.UserID, commentAffinity)
+
+	return comment, nil
+}
+
+// Comments lista los comentarios del tweet tweetID si viewerID puede verlo.
+func (r *repository) Comments(ctx context.Context, tweetID, viewerID string, query *dto.CommentQuery) ([]*models.Comment, string, error) {
+	if _, err := r.Get(ctx, tweetID, viewerID); err != nil {
+		return nil, "", err
+	}
+
+	size := query.Size
+	if size <= 0 {
+		size = defaultPageSize
+	}
+
+	db := r.db.WithContext(ctx).Where("tweet_id = ?", tweetID)
+	if query.ParentID != "" {
+		db = db.Where("parent_id = ?", query.ParentID)
+	} else {
+		db = db.Where("parent_id IS NULL")
+	}
+
+	// Por defecto los hilos se leen del más antiguo al más reciente
+	desc := query.Order == "desc"
+	if query.Cursor != "" {
+		createdAt, id, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if desc {
+			db = db.Where("created_at < ? OR (created_at = ? AND id < ?)", createdAt, createdAt, id)
+		} else {
+			db = db.Where("created_at > ? OR (created_at = ? AND id > ?)", createdAt, createdAt, id)
+		}
+	}
+	if desc {
+		db = db.Order("created_at DESC, id DESC")
+	} else {
+		db = db.Order("created_at ASC, id ASC")
+	}
+
+	// Se pide un elemento extra para saber si existe una página siguiente
+	var comments []*models.Comment
+	if err := db.Limit(size + 1).Find(&comments).Error; err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, "", fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+		}
+		return nil, "", fmt.Errorf("error al obtener los comentarios: %w", err)
+	}
+
+	nextCursor := ""
+	if len(comments) > size {
+		comments = comments[:size]
+		last := comments[size-1]
+		nextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return comments, nextCursor, nil
+}
+
+func (r *repository) DeleteComment(ctx context.Context, id, userID string) error {
+	// Tweet del comentario, si aún existe, cuyo contador se actualiza
+	var tweet *models.Tweet
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		comment := &models.Comment{}
+		if err := tx.First(comment, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrCommentNotFound
+			}
+			return fmt.Errorf("error al obtener el comentario: %w", err)
+		}
+
+		// Solo el autor puede eliminar su comentario
+		if comment.UserID != userID {
+			return models.ErrForbidden
+		}
+
+		if err := tx.Delete(comment).Error; err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+			}
+			return fmt.Errorf("error al eliminar el comentario: %w", err)
+		}
+
+		if comment.ParentID != nil {
+			if err := tx.Model(&models.Comment{}).
+				Where("id = ? AND replies > 0", *comment.ParentID).
+				UpdateColumn("replies", gorm.Expr("replies - ?", 1)).Error; err != nil {
+				return fmt.Errorf("error al decrementar las respuestas: %w", err)
+			}
+		}
+
+		commented := &models.Tweet{ID: comment.TweetID}
+		if err := tx.Unscoped().First(commented, "id = ?", comment.TweetID).Error; err != nil {
+			return fmt.Errorf("error al obtener el tweet: %w", err)
+		}
+		if commented.DeletedAt.Valid {
+			// El tweet ya no existe, no hay contador ni caché que mantener
+			return nil
+		}
+
+		tweet = commented
+		return r.updateCounter(tx, tweet, "count_comments", -1)
+	})
+
+	if err != nil || tweet == nil {
+		return err
+	}
+
+	return r.setTweet(ctx, tweet)
+}
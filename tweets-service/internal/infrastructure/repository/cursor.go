@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"tweet-service/internal/domain/models"
+)
+
+// encodeCursor genera un cursor opaco a partir de la fecha de creación y el ID
+// del último elemento devuelto, de modo que el orden sea estable aunque haya
+// registros con la misma fecha.
+func encodeCursor(createdAt time.Time, id string) string {
+	raw := fmt.Sprintf("%d:%s", createdAt.UnixNano(), id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", models.ErrInvalidCursor
+	}
+
+	nanos, id, found := strings.Cut(string(raw), ":")
+	if !found || id == "" {
+		return time.Time{}, "", models.ErrInvalidCursor
+	}
+
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, "", models.ErrInvalidCursor
+	}
+
+	return time.Unix(0, n), id, nil
+}
+
+// encodeScoreCursor genera un cursor para los resultados ordenados por
+// relevancia a partir de la puntuación y el ID del último elemento devuelto.
+func encodeScoreCursor(score float64, id string) string {
+	raw := fmt.Sprintf("%s:%s", strconv.FormatFloat(score, 'g', -1, 64), id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeScoreCursor(cursor string) (float64, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", models.ErrInvalidCursor
+	}
+
+	value, id, found := strings.Cut(string(raw), ":")
+	if !found || id == "" {
+		return 0, "", models.ErrInvalidCursor
+	}
+
+	score, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, "", models.ErrInvalidCursor
+	}
+
+	return score, id, nil
+}
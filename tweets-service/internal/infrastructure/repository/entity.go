@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"tweet-service/internal/domain/entities"
+	"tweet-service/internal/domain/models"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// nicknamesKey es el hash nickname -> id que mantiene el user-service
+	nicknamesKey = "nicknames"
+	maxTags      = 5
+	maxTagLength = 20
+)
+
+// tweetEntities son los tags y menciones que se guardan junto a un tweet.
+type tweetEntities struct {
+	tags     []string
+	mentions []models.Mention
+}
+
+// extractEntities une los tags enviados con los hashtags del contenido y
+// resuelve las menciones. Falla si el total de tags supera el límite.
+func (r *repository) extractEntities(ctx context.Context, content string, explicit []string) (*tweetEntities, error) {
+	parsed := entities.Parse(content)
+
+	tags, err := mergeTags(explicit, parsed.Hashtags)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := r.resolveMentions(ctx, parsed.Mentions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tweetEntities{tags: tags, mentions: mentions}, nil
+}
+
+// mergeTags devuelve los tags explícitos seguidos de los hashtags del
+// contenido, sin repetir nombres aunque difieran en mayúsculas. Falla si algún
+// hashtag supera la longitud máxima de un tag.
+func mergeTags(explicit []string, hashtags []entities.Entity) ([]string, error) {
+	seen := make(map[string]struct{})
+	var tags []string
+
+	add := func(name string) {
+		key := strings.ToLower(name)
+		if _, ok := seen[key]; ok || name == "" {
+			return
+		}
+		seen[key] = struct{}{}
+		tags = append(tags, name)
+	}
+
+	for _, tag := range explicit {
+		add(strings.TrimPrefix(cleanSpaces(tag), "#"))
+	}
+	for _, hashtag := range hashtags {
+		if utf8.RuneCountInString(hashtag.Text) > maxTagLength {
+			return nil, models.ErrTagTooLong
+		}
+		add(hashtag.Text)
+	}
+
+	if len(tags) > maxTags {
+		return nil, models.ErrTooManyTags
+	}
+
+	return tags, nil
+}
+
+// resolveMentions busca los nicknames mencionados en el índice del
+// user-service. Las menciones a usuarios inexistentes quedan como texto.
+func (r *repository) resolveMentions(ctx context.Context, mentions []entities.Entity) ([]models.Mention, error) {
+	if len(mentions) == 0 {
+		return nil, nil
+	}
+
+	nicknames := make([]string, len(mentions))
+	for i, mention := range mentions {
+		nicknames[i] = strings.ToLower(mention.Text)
+	}
+
+	ids, err := r.redis.HMGet(ctx, nicknamesKey, nicknames...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error al resolver las menciones: %w", err)
+	}
+
+	var resolved []models.Mention
+	for i, id := range ids {
+		userID, ok := id.(string)
+		if !ok {
+			continue
+		}
+		resolved = append(resolved, models.Mention{
+			UserID:   userID,
+			Nickname: mentions[i].Text,
+			Start:    mentions[i].Start,
+			End:      mentions[i].End,
+		})
+	}
+
+	return resolved, nil
+}
+
+// saveEntities asocia al tweet sus tags, creándolos si no existen, y guarda
+// sus menciones.
+func (r *repository) saveEntities(tx *gorm.DB, tweet *models.Tweet, extracted *tweetEntities) error {
+	if len(extracted.tags) > 0 {
+		var tagModels []*models.Tag
+		for _, tagName := range extracted.tags {
+			tagModel := &models.Tag{}
+
+			// Buscar o crear el tag
+			if err := tx.Where("name = ?", tagName).FirstOrCreate(tagModel, &models.Tag{Name: tagName}).Error; err != nil {
+				return fmt.Errorf("error al crear o encontrar el tag '%s': %w", tagName, err)
+			}
+
+			tagModels = append(tagModels, tagModel)
+		}
+
+		// Asociar tags al tweet
+		if err := tx.Model(tweet).Association("Tags").Append(tagModels); err != nil {
+			return fmt.Errorf("error al asociar tags con el tweet: %w", err)
+		}
+	}
+
+	if len(extracted.mentions) > 0 {
+		for i := range extracted.mentions {
+			extracted.mentions[i].TweetID = tweet.ID
+		}
+		if err := tx.Create(&extracted.mentions).Error; err != nil {
+			return fmt.Errorf("error al guardar las menciones: %w", err)
+		}
+		tweet.Mentions = extracted.mentions
+	}
+
+	return nil
+}
@@ -5,13 +5,28 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
+	"time"
 	"tweet-service/internal/application/dto"
 	"tweet-service/internal/domain/models"
 	"tweet-service/internal/interfaces"
 
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// tweetStream es el stream de Redis donde se publican los tweets nuevos y
+	// eliminados para que el timeline-service actualice los timelines.
+	tweetStream = "tweet_stream"
+	// deleteEvent identifica en el stream los tweets eliminados
+	deleteEvent = "delete"
+	// deletedTTL es el tiempo que se conserva la marca deleted_tweets:<id>, con
+	// la que el timeline-service distingue un tweet eliminado de uno que aún no
+	// puede leer
+	deletedTTL = 7 * 24 * time.Hour
 )
 
 type repository struct {
@@ -26,11 +41,18 @@ func NewRepository(db *gorm.DB, redis *redis.Client) interfaces.TweetRepository
 func (r *repository) Create(ctx context.Context, createTweetDTO *dto.CreateTweet) (*models.Tweet, error) {
 	var tweet *models.Tweet
 
-	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	// Los hashtags y menciones del contenido se resuelven antes de la transacción
+	extracted, err := r.extractEntities(ctx, createTweetDTO.Content, createTweetDTO.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Asignación explícita de campos
 		tweet = &models.Tweet{
 			Content: createTweetDTO.Content,
 			UserID:  createTweetDTO.UserID,
+			Kind:    models.TweetKindOriginal,
 			// Otros campos necesarios...
 		}
 
@@ -42,28 +64,11 @@ func (r *repository) Create(ctx context.Context, createTweetDTO *dto.CreateTweet
 			return fmt.Errorf("error al crear el tweet: %w", err)
 		}
 
-		// Manejo de tags
-		if len(createTweetDTO.Tags) > 0 {
-			var tagModels []*models.Tag
-			for _, tag := range createTweetDTO.Tags {
-				tagName := cleanSpaces(tag)
-				tagModel := &models.Tag{}
-
-				// Buscar o crear el tag
-				if err := tx.Where("name = ?", tagName).FirstOrCreate(tagModel, &models.Tag{Name: tagName}).Error; err != nil {
-					return fmt.Errorf("error al crear o encontrar el tag '%s': %w", tagName, err)
-				}
-
-				tagModels = append(tagModels, tagModel)
-			}
-
-			// Asociar tags al tweet
-			if err := tx.Model(tweet).Association("Tags").Append(tagModels); err != nil {
-				return fmt.Errorf("error al asociar tags con el tweet: %w", err)
-			}
+		if err := r.saveEntities(tx, tweet, extracted); err != nil {
+			return err
 		}
 
-		return nil
+		return r.indexTweet(tx, tweet, extracted.tags)
 	})
 
 	if err != nil {
@@ -88,8 +93,21 @@ func (r *repository) cacheTweet(ctx context.Context, tweet *models.Tweet) error
 	pipe := r.redis.Pipeline()
 	pipe.Set(ctx, tweetKey, tweetData, 0)
 
-	if err := pipe.LPush(ctx, "tweet_queue", tweet.ID).Err(); err != nil {
-		return fmt.Errorf("error al agregar el tweet a la cola: %w", err)
+	// Índice por autor que usa el timeline-service para completar timelines
+	pipe.ZAdd(ctx, fmt.Sprintf("user_tweets:%s", tweet.UserID), redis.Z{
+		Score:  float64(tweet.CreatedAt.UnixMilli()),
+		Member: tweet.ID,
+	})
+
+	// Contadores de tendencias de los tags del tweet
+	countTags(ctx, pipe, tweet.Tags, tweet.CreatedAt, 1)
+
+	// Publicar el tweet en el stream que consume el timeline-service
+	if err := pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: tweetStream,
+		Values: map[string]interface{}{"tweet_id": tweet.ID},
+	}).Err(); err != nil {
+		return fmt.Errorf("error al agregar el tweet al stream: %w", err)
 	}
 
 	_, err = pipe.Exec(ctx)
@@ -100,11 +118,131 @@ func (r *repository) cacheTweet(ctx context.Context, tweet *models.Tweet) error
 	return nil
 }
 
-func (r *repository) Delete(ctx context.Context, id string) error {
+// SyncCache vuelve a escribir en Redis la copia de todos los tweets y el índice
+// user_tweets:<id>, para que el timeline-service pueda completar timelines con
+// los tweets creados antes de existir el índice. No se publican en el stream
+// ni se cuentan en las tendencias.
+func (r *repository) SyncCache(ctx context.Context) error {
+	var tweets []*models.Tweet
+	result := r.db.WithContext(ctx).Preload("Mentions").FindInBatches(&tweets, 500, func(tx *gorm.DB, batch int) error {
+		pipe := r.redis.Pipeline()
+		for _, tweet := range tweets {
+			tweetData, err := newTweet(tweet)
+			if err != nil {
+				return fmt.Errorf("error al serializar el tweet a JSON: %w", err)
+			}
+
+			pipe.Set(ctx, fmt.Sprintf("tweets:%s", tweet.ID), tweetData, 0)
+			pipe.ZAdd(ctx, fmt.Sprintf("user_tweets:%s", tweet.UserID), redis.Z{
+				Score:  float64(tweet.CreatedAt.UnixMilli()),
+				Member: tweet.ID,
+			})
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("error al guardar los tweets en Redis: %w", err)
+		}
+		return nil
+	})
+	if result.Error != nil {
+		return fmt.Errorf("error al sincronizar los tweets en Redis: %w", result.Error)
+	}
+
+	return nil
+}
+
+// Get devuelve el tweet id si userID puede verlo.
+func (r *repository) Get(ctx context.Context, id, userID string) (*models.Tweet, error) {
+	tweet, err := r.getTweet(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.checkVisible(ctx, tweet.UserID, userID); err != nil {
+		return nil, err
+	}
+
+	return tweet, nil
+}
+
+func (r *repository) getTweet(ctx context.Context, id string) (*models.Tweet, error) {
+	tweetKey := fmt.Sprintf("tweets:%s", id)
+
+	// Intentar primero con la caché
+	tweetData, err := r.redis.Get(ctx, tweetKey).Bytes()
+	if err == nil {
+		return parseTweet(id, tweetData)
+	}
+	if err != redis.Nil {
+		log.Printf("Error al leer el tweet %s de Redis: %v", id, err)
+	}
+
+	tweet := &models.Tweet{}
+	if err := r.db.WithContext(ctx).Preload("Mentions").First(tweet, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrTweetNotFound
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+		}
+		return nil, fmt.Errorf("error al obtener el tweet: %w", err)
+	}
+
+	// Repoblar la caché sin volver a encolar el tweet
+	if data, err := newTweet(tweet); err == nil {
+		if err := r.redis.Set(ctx, tweetKey, data, 0).Err(); err != nil {
+			log.Printf("Error al repoblar el tweet %s en Redis: %v", id, err)
+		}
+	}
+
+	return tweet, nil
+}
+
+// UserTweets lista los tweets de userID si viewerID puede verlos.
+func (r *repository) UserTweets(ctx context.Context, userID, viewerID string, query *dto.TweetQuery) ([]*models.Tweet, string, error) {
+	if err := r.checkVisible(ctx, userID, viewerID); err != nil {
+		return nil, "", err
+	}
+
+	size := query.Size
+	if size <= 0 {
+		size = defaultPageSize
+	}
+
+	db := r.db.WithContext(ctx).Preload("Mentions").Where("user_id = ?", userID)
+	if query.Cursor != "" {
+		createdAt, id, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		db = db.Where("created_at < ? OR (created_at = ? AND id < ?)", createdAt, createdAt, id)
+	}
+
+	// Se pide un elemento extra para saber si existe una página siguiente
+	var tweets []*models.Tweet
+	if err := db.Order("created_at DESC, id DESC").Limit(size + 1).Find(&tweets).Error; err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, "", fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+		}
+		return nil, "", fmt.Errorf("error al obtener los tweets: %w", err)
+	}
+
+	nextCursor := ""
+	if len(tweets) > size {
+		tweets = tweets[:size]
+		last := tweets[size-1]
+		nextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return tweets, nextCursor, nil
+}
+
+// Delete elimina un tweet de su autor. Los administradores pueden eliminar
+// cualquier tweet, dejando constancia en el registro de auditoría.
+func (r *repository) Delete(ctx context.Context, id, userID string, admin bool) error {
 	tweet := &models.Tweet{}
 	if err := r.db.WithContext(ctx).First(tweet, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("tweet no encontrado")
+			return models.ErrTweetNotFound
 		}
 		if ctx.Err() == context.DeadlineExceeded {
 			return fmt.Errorf("operación cancelada por exceder el límite de tiempo")
@@ -112,8 +250,33 @@ func (r *repository) Delete(ctx context.Context, id string) error {
 		return fmt.Errorf("error al obtener el tweet: %w", err)
 	}
 
+	if tweet.UserID != userID && !admin {
+		return models.ErrForbidden
+	}
+
+	// Los tags se leen antes de borrar las asociaciones para descontarlos de las tendencias
+	var tags []models.Tag
+	// Tweet referenciado por un retweet o cita, cuyo contador se actualiza
+	original := &models.Tweet{}
+
 	// Iniciar transacción
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(tweet).Association("Tags").Find(&tags); err != nil {
+			return fmt.Errorf("error al obtener los tags del tweet: %w", err)
+		}
+
+		if tweet.UserID != userID {
+			deletion := &models.TweetDeletion{
+				TweetID:  tweet.ID,
+				AuthorID: tweet.UserID,
+				AdminID:  userID,
+				Content:  tweet.Content,
+			}
+			if err := tx.Create(deletion).Error; err != nil {
+				return fmt.Errorf("error al registrar la eliminación: %w", err)
+			}
+		}
+
 		// Eliminar el tweet de la base de datos
 		if err := tx.Delete(tweet).Error; err != nil {
 			if ctx.Err() == context.DeadlineExceeded {
@@ -126,6 +289,22 @@ func (r *repository) Delete(ctx context.Context, id string) error {
 		if err := tx.Model(tweet).Association("Tags").Clear(); err != nil {
 			return fmt.Errorf("error al eliminar asociaciones de tags: %w", err)
 		}
+		if err := tx.Where("tweet_id = ?", tweet.ID).Delete(&models.Mention{}).Error; err != nil {
+			return fmt.Errorf("error al eliminar las menciones: %w", err)
+		}
+		if err := r.unindexTweet(tx, tweet.ID); err != nil {
+			return err
+		}
+
+		// Un retweet o cita eliminado deja de contar como compartido en el original
+		if tweet.ReferenceID != nil {
+			if err := tx.Where("id = ?", *tweet.ReferenceID).Limit(1).Find(original).Error; err != nil {
+				return fmt.Errorf("error al obtener el tweet original: %w", err)
+			}
+			if original.ID != "" {
+				return r.updateCounter(tx, original, "shares", -1)
+			}
+		}
 
 		return nil
 	})
@@ -134,14 +313,32 @@ func (r *repository) Delete(ctx context.Context, id string) error {
 		return err
 	}
 
+	if original.ID != "" {
+		if err := r.setTweet(ctx, original); err != nil {
+			return err
+		}
+	}
+
 	// Eliminar el tweet de Redis
-	tweetKey := fmt.Sprintf("tweet:%s", tweet.ID)
-	timelineKey := fmt.Sprintf("timeline:%s", tweet.UserID)
+	tweetKey := fmt.Sprintf("tweets:%s", tweet.ID)
 
 	// Utilizar Pipeline para agrupar operaciones
-	pipe := r.redis.Pipeline()
+	pipe := r.redis.TxPipeline()
 	pipe.Del(ctx, tweetKey)
-	pipe.ZRem(ctx, timelineKey, tweet.ID)
+	pipe.Set(ctx, fmt.Sprintf("deleted_tweets:%s", tweet.ID), 1, deletedTTL)
+	pipe.ZRem(ctx, fmt.Sprintf("user_tweets:%s", tweet.UserID), tweet.ID)
+	countTags(ctx, pipe, tags, tweet.CreatedAt, -1)
+
+	// Publicar el borrado para que el timeline-service lo retire de los
+	// timelines de los seguidores
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: tweetStream,
+		Values: map[string]interface{}{
+			"type":     deleteEvent,
+			"tweet_id": tweet.ID,
+			"user_id":  tweet.UserID,
+		},
+	})
 
 	_, err = pipe.Exec(ctx)
 	if err != nil {
@@ -151,6 +348,235 @@ func (r *repository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (r *repository) Retweet(ctx context.Context, id, userID string) (*models.Tweet, error) {
+	return r.share(ctx, id, userID, models.TweetKindRetweet, "")
+}
+
+func (r *repository) Quote(ctx context.Context, id, userID string, quote *dto.CreateQuote) (*models.Tweet, error) {
+	return r.share(ctx, id, userID, models.TweetKindQuote, quote.Content)
+}
+
+// share crea un retweet o una cita que referencia al tweet original. Compartir
+// un retweet referencia siempre al tweet que lo originó.
+func (r *repository) share(ctx context.Context, id, userID, kind, content string) (*models.Tweet, error) {
+	var tweet *models.Tweet
+	original := &models.Tweet{}
+
+	// Las citas pueden incluir hashtags y menciones propios
+	extracted, err := r.extractEntities(ctx, content, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(original, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrTweetNotFound
+			}
+			return fmt.Errorf("error al obtener el tweet: %w", err)
+		}
+
+		// No se puede compartir el tweet de un usuario con el que existe un
+		// bloqueo ni el de una cuenta privada que no se sigue
+		if err := r.checkBlocked(ctx, userID, original.UserID); err != nil {
+			return err
+		}
+		if err := r.checkVisible(ctx, original.UserID, userID); err != nil {
+			return err
+		}
+
+		if original.Kind == models.TweetKindRetweet && original.ReferenceID != nil {
+			// Se carga en una estructura nueva: con la clave primaria ya asignada
+			// GORM filtraría también por el id del retweet
+			referenced := &models.Tweet{}
+			if err := tx.First(referenced, "id = ?", *original.ReferenceID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return models.ErrTweetNotFound
+				}
+				return fmt.Errorf("error al obtener el tweet original: %w", err)
+			}
+			if err := r.checkBlocked(ctx, userID, referenced.UserID); err != nil {
+				return err
+			}
+			if err := r.checkVisible(ctx, referenced.UserID, userID); err != nil {
+				return err
+			}
+			original = referenced
+		}
+
+		// Un usuario solo puede retuitear una vez el mismo tweet
+		if kind == models.TweetKindRetweet {
+			var count int64
+			if err := tx.Model(&models.Tweet{}).
+				Where("user_id = ? AND kind = ? AND reference_id = ?", userID, kind, original.ID).
+				Count(&count).Error; err != nil {
+				return fmt.Errorf("error al verificar el retweet: %w", err)
+			}
+			if count > 0 {
+				return models.ErrAlreadyShared
+			}
+		}
+
+		tweet = &models.Tweet{
+			UserID:      userID,
+			Kind:        kind,
+			ReferenceID: &original.ID,
+			Content:     content,
+		}
+		if err := tx.Create(tweet).Error; err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+			}
+			return fmt.Errorf("error al compartir el tweet: %w", err)
+		}
+
+		if err := r.saveEntities(tx, tweet, extracted); err != nil {
+			return err
+		}
+		if err := r.indexTweet(tx, tweet, extracted.tags); err != nil {
+			return err
+		}
+
+		return r.updateCounter(tx, original, "shares", 1)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.setTweet(ctx, original); err != nil {
+		return nil, err
+	}
+	if err := r.cacheTweet(ctx, tweet); err != nil {
+		return nil, err
+	}
+	r.recordAffinity(ctx, userID, original.UserID, shareAffinity)
+
+	return tweet, nil
+}
+
+func (r *repository) Like(ctx context.Context, id, userID string) (*models.Tweet, error) {
+	tweet := &models.Tweet{}
+	liked := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(tweet, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrTweetNotFound
+			}
+			return fmt.Errorf("error al obtener el tweet: %w", err)
+		}
+
+		if err := r.checkBlocked(ctx, userID, tweet.UserID); err != nil {
+			return err
+		}
+		if err := r.checkVisible(ctx, tweet.UserID, userID); err != nil {
+			return err
+		}
+
+		// Registrar el like; si ya existe, incluso si otra petición lo creó al
+		// mismo tiempo, la operación no tiene efecto
+		like := &models.TweetLike{TweetID: id, UserID: userID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(like)
+		if result.Error != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+			}
+			return fmt.Errorf("error al registrar el like: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		liked = true
+
+		return r.updateCounter(tx, tweet, "likes", 1)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	if liked {
+		if err := r.setTweet(ctx, tweet); err != nil {
+			return nil, err
+		}
+		r.recordAffinity(ctx, userID, tweet.UserID, likeAffinity)
+	}
+
+	return tweet, nil
+}
+
+func (r *repository) Unlike(ctx context.Context, id, userID string) (*models.Tweet, error) {
+	tweet := &models.Tweet{}
+	unliked := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(tweet, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrTweetNotFound
+			}
+			return fmt.Errorf("error al obtener el tweet: %w", err)
+		}
+
+		// Eliminar el like; si no existe la operación no tiene efecto
+		result := tx.Where("tweet_id = ? AND user_id = ?", id, userID).Delete(&models.TweetLike{})
+		if result.Error != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+			}
+			return fmt.Errorf("error al eliminar el like: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		unliked = true
+
+		return r.updateCounter(tx, tweet, "likes", -1)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	if unliked {
+		if err := r.setTweet(ctx, tweet); err != nil {
+			return nil, err
+		}
+		r.recordAffinity(ctx, userID, tweet.UserID, -likeAffinity)
+	}
+
+	return tweet, nil
+}
+
+// updateCounter ajusta un contador del tweet dentro de la transacción y
+// recarga el tweet con el valor actual. El payload en caché se sobrescribe
+// con setTweet una vez confirmada la transacción.
+func (r *repository) updateCounter(tx *gorm.DB, tweet *models.Tweet, column string, delta int) error {
+	if err := tx.Model(tweet).
+		Where(column+" + ? >= 0", delta).
+		UpdateColumn(column, gorm.Expr(column+" + ?", delta)).Error; err != nil {
+		return fmt.Errorf("error al actualizar el contador %s: %w", column, err)
+	}
+
+	if err := tx.Preload("Mentions").First(tweet, "id = ?", tweet.ID).Error; err != nil {
+		return fmt.Errorf("error al obtener el tweet: %w", err)
+	}
+
+	return nil
+}
+
+// setTweet sobrescribe el payload en caché del tweet para que el timeline
+// muestre los contadores actuales.
+func (r *repository) setTweet(ctx context.Context, tweet *models.Tweet) error {
+	tweetData, err := newTweet(tweet)
+	if err != nil {
+		return err
+	}
+	if err := r.redis.Set(ctx, fmt.Sprintf("tweets:%s", tweet.ID), tweetData, 0).Err(); err != nil {
+		return fmt.Errorf("error al actualizar el tweet en Redis: %w", err)
+	}
+
+	return nil
+}
+
 func cleanSpaces(input string) string {
 	trimmed := strings.TrimSpace(input)
 	words := strings.Fields(trimmed)
@@ -158,22 +584,83 @@ func cleanSpaces(input string) string {
 	return cleaned
 }
 
+// cachedTweet es la representación del tweet almacenada en tweets:<id>, que
+// el timeline-service lee para construir los timelines.
+type cachedTweet struct {
+	UserID      string    `json:"userId"`
+	Kind        string    `json:"kind"`
+	ReferenceID *string   `json:"referenceId,omitempty"`
+	Content     string    `json:"content"`
+	Likes       int       `json:"likes"`
+	Shares      int       `json:"shares"`
+	Comments    int       `json:"comments"`
+	CreatedAt   time.Time `json:"createdAt"`
+
+	Mentions []cachedMention `json:"mentions,omitempty"`
+}
+
+type cachedMention struct {
+	UserID   string `json:"userId"`
+	Nickname string `json:"nickname"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+}
+
 func newTweet(tw *models.Tweet) ([]byte, error) {
-	jsonData, err := json.Marshal(struct {
-		UserID   string `json:"userId"`
-		Content  string `json:"content"`
-		Likes    int    `json:"likes"`
-		Shares   int    `json:"shares"`
-		Comments int    `json:"comments"`
-	}{
-		UserID:   tw.UserID,
-		Content:  tw.Content,
-		Likes:    tw.Likes,
-		Shares:   tw.Shares,
-		Comments: tw.CountComments,
+	mentions := make([]cachedMention, len(tw.Mentions))
+	for i, mention := range tw.Mentions {
+		mentions[i] = cachedMention{
+			UserID:   mention.UserID,
+			Nickname: mention.Nickname,
+			Start:    mention.Start,
+			End:      mention.End,
+		}
+	}
+
+	jsonData, err := json.Marshal(cachedTweet{
+		UserID:      tw.UserID,
+		Kind:        tw.Kind,
+		ReferenceID: tw.ReferenceID,
+		Content:     tw.Content,
+		Likes:       tw.Likes,
+		Shares:      tw.Shares,
+		Comments:    tw.CountComments,
+		CreatedAt:   tw.CreatedAt,
+		Mentions:    mentions,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error al serializar el tweet a JSON: %w", err)
 	}
 	return jsonData, nil
 }
+
+func parseTweet(id string, data []byte) (*models.Tweet, error) {
+	var cached cachedTweet
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, fmt.Errorf("error al deserializar el tweet: %w", err)
+	}
+
+	mentions := make([]models.Mention, len(cached.Mentions))
+	for i, mention := range cached.Mentions {
+		mentions[i] = models.Mention{
+			TweetID:  id,
+			UserID:   mention.UserID,
+			Nickname: mention.Nickname,
+			Start:    mention.Start,
+			End:      mention.End,
+		}
+	}
+
+	return &models.Tweet{
+		ID:            id,
+		UserID:        cached.UserID,
+		Kind:          cached.Kind,
+		ReferenceID:   cached.ReferenceID,
+		Content:       cached.Content,
+		Likes:         cached.Likes,
+		Shares:        cached.Shares,
+		CountComments: cached.Comments,
+		CreatedAt:     cached.CreatedAt,
+		Mentions:      mentions,
+	}, nil
+}
@@ -1 +1,469 @@
 package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"tweet-service/internal/application/dto"
+	"tweet-service/internal/domain/entities"
+	"tweet-service/internal/domain/models"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func newTestRepository(t *testing.T) (*repository, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to in-memory database: %v", err)
+	}
+
+	// Migrar los modelos
+	err = db.AutoMigrate(&models.Tweet{}, &models.Tag{}, &models.Comment{}, &models.TweetLike{}, &models.TweetDeletion{}, &models.Mention{})
+	if err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	if err := MigrateSearch(db); err != nil {
+		t.Fatalf("Failed to migrate search index: %v", err)
+	}
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	return &repository{db: db, redis: client}, db
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Now()
+
+	cursor := encodeCursor(createdAt, "abc")
+	decodedAt, id, err := decodeCursor(cursor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", id)
+	assert.True(t, createdAt.Equal(decodedAt))
+
+	_, _, err = decodeCursor("no es un cursor")
+	assert.ErrorIs(t, err, models.ErrInvalidCursor)
+}
+
+func TestRepository_Comments(t *testing.T) {
+	repo, db := newTestRepository(t)
+
+	tweet := &models.Tweet{UserID: "user", Content: "Tweet"}
+	assert.NoError(t, db.Create(tweet).Error)
+
+	base := time.Now()
+	for i := 0; i < 5; i++ {
+		comment := &models.Comment{
+			TweetID:   tweet.ID,
+			UserID:    "user",
+			Content:   "Comentario",
+			CreatedAt: base.Add(time.Duration(i) * time.Second),
+		}
+		assert.NoError(t, db.Create(comment).Error)
+	}
+
+	// Recorrer todas las páginas del más antiguo al más reciente
+	var seen []*models.Comment
+	query := &dto.CommentQuery{Size: 2}
+	for {
+		comments, next, err := repo.Comments(context.Background(), tweet.ID, "viewer", query)
+		assert.NoError(t, err)
+		seen = append(seen, comments...)
+		if next == "" {
+			break
+		}
+		query.Cursor = next
+	}
+
+	assert.Len(t, seen, 5)
+	for i := 1; i < len(seen); i++ {
+		assert.True(t, seen[i-1].CreatedAt.Before(seen[i].CreatedAt))
+	}
+
+	// Orden inverso
+	comments, _, err := repo.Comments(context.Background(), tweet.ID, "viewer", &dto.CommentQuery{Size: 1, Order: "desc"})
+	assert.NoError(t, err)
+	assert.Equal(t, seen[4].ID, comments[0].ID)
+
+	_, _, err = repo.Comments(context.Background(), "inexistente", "viewer", &dto.CommentQuery{})
+	assert.ErrorIs(t, err, models.ErrTweetNotFound)
+}
+
+func TestRepository_UserTweets(t *testing.T) {
+	repo, db := newTestRepository(t)
+
+	base := time.Now()
+	for i := 0; i < 3; i++ {
+		tweet := &models.Tweet{
+			UserID:    "user",
+			Kind:      models.TweetKindOriginal,
+			Content:   "Tweet",
+			CreatedAt: base.Add(time.Duration(i) * time.Second),
+		}
+		assert.NoError(t, db.Create(tweet).Error)
+	}
+	assert.NoError(t, db.Create(&models.Tweet{UserID: "otro", Content: "Ajeno"}).Error)
+
+	first, next, err := repo.UserTweets(context.Background(), "user", "user", &dto.TweetQuery{Size: 2})
+	assert.NoError(t, err)
+	assert.Len(t, first, 2)
+	assert.NotEmpty(t, next)
+	assert.True(t, first[0].CreatedAt.After(first[1].CreatedAt))
+
+	second, next, err := repo.UserTweets(context.Background(), "user", "user", &dto.TweetQuery{Size: 2, Cursor: next})
+	assert.NoError(t, err)
+	assert.Len(t, second, 1)
+	assert.Empty(t, next)
+	assert.True(t, second[0].CreatedAt.Before(first[1].CreatedAt))
+}
+
+func TestRepository_SyncCache(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	createdAt := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	tweet := &models.Tweet{UserID: "author", Kind: models.TweetKindOriginal, Content: "Tweet", CreatedAt: createdAt}
+	deleted := &models.Tweet{UserID: "author", Kind: models.TweetKindOriginal, Content: "Borrado"}
+	assert.NoError(t, db.Create(tweet).Error)
+	assert.NoError(t, db.Create(deleted).Error)
+	assert.NoError(t, db.Delete(deleted).Error)
+
+	assert.NoError(t, repo.SyncCache(ctx))
+
+	ids, err := repo.redis.ZRangeWithScores(ctx, "user_tweets:author", 0, -1).Result()
+	assert.NoError(t, err)
+	if assert.Len(t, ids, 1) {
+		assert.Equal(t, tweet.ID, ids[0].Member)
+		assert.Equal(t, float64(createdAt.UnixMilli()), ids[0].Score)
+	}
+
+	cached, err := repo.getTweet(ctx, tweet.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Tweet", cached.Content)
+
+	// Sincronizar no vuelve a publicar los tweets para distribuirlos
+	length, err := repo.redis.XLen(ctx, tweetStream).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), length)
+}
+
+func TestRepository_Delete_Forbidden(t *testing.T) {
+	repo, db := newTestRepository(t)
+
+	tweet := &models.Tweet{UserID: "author", Content: "Tweet"}
+	assert.NoError(t, db.Create(tweet).Error)
+
+	err := repo.Delete(context.Background(), tweet.ID, "other", false)
+	assert.ErrorIs(t, err, models.ErrForbidden)
+
+	var count int64
+	db.Model(&models.Tweet{}).Where("id = ?", tweet.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestRepository_LikeUnlike(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	tweet := &models.Tweet{UserID: "author", Content: "Tweet"}
+	assert.NoError(t, db.Create(tweet).Error)
+
+	// Un segundo like del mismo usuario no cambia el contador
+	for i := 0; i < 2; i++ {
+		liked, err := repo.Like(ctx, tweet.ID, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, liked.Likes)
+	}
+
+	cached, err := repo.getTweet(ctx, tweet.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cached.Likes)
+
+	// Un like creado por una petición concurrente no produce error ni se cuenta dos veces
+	assert.NoError(t, db.Create(&models.TweetLike{TweetID: tweet.ID, UserID: "other"}).Error)
+	liked, err := repo.Like(ctx, tweet.ID, "other")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, liked.Likes)
+
+	for i := 0; i < 2; i++ {
+		unliked, err := repo.Unlike(ctx, tweet.ID, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, unliked.Likes)
+	}
+
+	cached, err = repo.getTweet(ctx, tweet.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cached.Likes)
+
+	_, err = repo.Like(ctx, "inexistente", "user")
+	assert.ErrorIs(t, err, models.ErrTweetNotFound)
+}
+
+func TestRepository_Delete(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	tweet, err := repo.Create(ctx, &dto.CreateTweet{UserID: "author", Content: "Tweet"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.Delete(ctx, tweet.ID, "author", false))
+
+	_, err = repo.Get(ctx, tweet.ID, "author")
+	assert.ErrorIs(t, err, models.ErrTweetNotFound)
+	var count int64
+	db.Model(&models.Tweet{}).Where("id = ?", tweet.ID).Count(&count)
+	assert.Equal(t, int64(0), count)
+
+	// La marca de borrado permite al timeline-service descartar la entrada de
+	// creación aún pendiente
+	ttl, err := repo.redis.TTL(ctx, "deleted_tweets:"+tweet.ID).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, deletedTTL, ttl)
+
+	entries, err := repo.redis.XRange(ctx, tweetStream, "-", "+").Result()
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, deleteEvent, entries[1].Values["type"])
+	}
+}
+
+func TestRepository_Retweet(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	tweet := &models.Tweet{UserID: "author", Kind: models.TweetKindOriginal, Content: "Tweet"}
+	assert.NoError(t, db.Create(tweet).Error)
+
+	retweet, err := repo.Retweet(ctx, tweet.ID, "user")
+	assert.NoError(t, err)
+	assert.Equal(t, models.TweetKindRetweet, retweet.Kind)
+	assert.Equal(t, tweet.ID, *retweet.ReferenceID)
+
+	_, err = repo.Retweet(ctx, tweet.ID, "user")
+	assert.ErrorIs(t, err, models.ErrAlreadyShared)
+
+	// Retuitear un retweet referencia al tweet que lo originó
+	again, err := repo.Retweet(ctx, retweet.ID, "other")
+	assert.NoError(t, err)
+	assert.Equal(t, tweet.ID, *again.ReferenceID)
+
+	_, err = repo.Retweet(ctx, retweet.ID, "user")
+	assert.ErrorIs(t, err, models.ErrAlreadyShared)
+
+	cached, err := repo.getTweet(ctx, tweet.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cached.Shares)
+
+	_, err = repo.Retweet(ctx, "inexistente", "user")
+	assert.ErrorIs(t, err, models.ErrTweetNotFound)
+}
+
+func TestRepository_Quote(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	tweet := &models.Tweet{UserID: "author", Kind: models.TweetKindOriginal, Content: "Tweet"}
+	assert.NoError(t, db.Create(tweet).Error)
+
+	retweet, err := repo.Retweet(ctx, tweet.ID, "user")
+	assert.NoError(t, err)
+
+	// Citar un retweet cita al tweet original y se puede citar varias veces
+	for i := 0; i < 2; i++ {
+		quote, err := repo.Quote(ctx, retweet.ID, "user", &dto.CreateQuote{Content: "Mi opinión"})
+		assert.NoError(t, err)
+		assert.Equal(t, models.TweetKindQuote, quote.Kind)
+		assert.Equal(t, tweet.ID, *quote.ReferenceID)
+		assert.Equal(t, "Mi opinión", quote.Content)
+	}
+
+	var original models.Tweet
+	assert.NoError(t, db.First(&original, "id = ?", tweet.ID).Error)
+	assert.Equal(t, 3, original.Shares)
+}
+
+func TestRepository_Share_Blocked(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	tweet := &models.Tweet{UserID: "author", Kind: models.TweetKindOriginal, Content: "Tweet"}
+	assert.NoError(t, db.Create(tweet).Error)
+
+	retweet, err := repo.Retweet(ctx, tweet.ID, "user")
+	assert.NoError(t, err)
+
+	// El autor original bloqueó a other: tampoco puede compartir el retweet
+	assert.NoError(t, repo.redis.SAdd(ctx, "blocking:author", "other").Err())
+
+	_, err = repo.Retweet(ctx, tweet.ID, "other")
+	assert.ErrorIs(t, err, models.ErrBlocked)
+	_, err = repo.Retweet(ctx, retweet.ID, "other")
+	assert.ErrorIs(t, err, models.ErrBlocked)
+	_, err = repo.Quote(ctx, tweet.ID, "other", &dto.CreateQuote{Content: "Mi opinión"})
+	assert.ErrorIs(t, err, models.ErrBlocked)
+
+	var original models.Tweet
+	assert.NoError(t, db.First(&original, "id = ?", tweet.ID).Error)
+	assert.Equal(t, 1, original.Shares)
+}
+
+func TestRepository_PrivateAccount(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	tweet := &models.Tweet{UserID: "author", Kind: models.TweetKindOriginal, Content: "Tweet"}
+	assert.NoError(t, db.Create(tweet).Error)
+	assert.NoError(t, repo.redis.SAdd(ctx, privateUsersKey, "author").Err())
+	assert.NoError(t, repo.redis.SAdd(ctx, "followers:author", "follower").Err())
+
+	// Quien no sigue a la cuenta privada no puede leer ni interactuar con sus tweets
+	_, err := repo.Get(ctx, tweet.ID, "stranger")
+	assert.ErrorIs(t, err, models.ErrPrivateAccount)
+	_, err = repo.Like(ctx, tweet.ID, "stranger")
+	assert.ErrorIs(t, err, models.ErrPrivateAccount)
+	_, err = repo.Retweet(ctx, tweet.ID, "stranger")
+	assert.ErrorIs(t, err, models.ErrPrivateAccount)
+	_, err = repo.Quote(ctx, tweet.ID, "stranger", &dto.CreateQuote{Content: "Mi opinión"})
+	assert.ErrorIs(t, err, models.ErrPrivateAccount)
+	_, err = repo.CreateComment(ctx, tweet.ID, "stranger", &dto.CreateComment{Content: "Comentario"})
+	assert.ErrorIs(t, err, models.ErrPrivateAccount)
+	_, _, err = repo.Comments(ctx, tweet.ID, "stranger", &dto.CommentQuery{})
+	assert.ErrorIs(t, err, models.ErrPrivateAccount)
+
+	// Tampoco a través del retweet de un seguidor
+	retweet, err := repo.Retweet(ctx, tweet.ID, "follower")
+	assert.NoError(t, err)
+	_, err = repo.Quote(ctx, retweet.ID, "stranger", &dto.CreateQuote{Content: "Mi opinión"})
+	assert.ErrorIs(t, err, models.ErrPrivateAccount)
+
+	// Los seguidores aprobados sí pueden
+	_, err = repo.Like(ctx, tweet.ID, "follower")
+	assert.NoError(t, err)
+	_, err = repo.CreateComment(ctx, tweet.ID, "follower", &dto.CreateComment{Content: "Comentario"})
+	assert.NoError(t, err)
+	comments, _, err := repo.Comments(ctx, tweet.ID, "follower", &dto.CommentQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+
+	var stored models.Tweet
+	assert.NoError(t, db.First(&stored, "id = ?", tweet.ID).Error)
+	assert.Equal(t, 1, stored.Likes)
+	assert.Equal(t, 1, stored.Shares)
+	assert.Equal(t, 1, stored.CountComments)
+}
+
+func TestRepository_TagTweets(t *testing.T) {
+	repo, db := newTestRepository(t)
+
+	golang := models.Tag{Name: "golang"}
+	other := models.Tag{Name: "rust"}
+	assert.NoError(t, db.Create(&golang).Error)
+	assert.NoError(t, db.Create(&other).Error)
+
+	base := time.Now()
+	for i := 0; i < 3; i++ {
+		tweet := &models.Tweet{
+			UserID:    "user",
+			Content:   "Tweet",
+			Tags:      []models.Tag{golang},
+			CreatedAt: base.Add(time.Duration(i) * time.Second),
+		}
+		assert.NoError(t, db.Create(tweet).Error)
+	}
+	assert.NoError(t, db.Create(&models.Tweet{UserID: "user", Content: "Otro", Tags: []models.Tag{other}}).Error)
+
+	first, next, err := repo.TagTweets(context.Background(), "golang", "user", &dto.TweetQuery{Size: 2})
+	assert.NoError(t, err)
+	assert.Len(t, first, 2)
+	assert.NotEmpty(t, next)
+	assert.True(t, first[0].CreatedAt.After(first[1].CreatedAt))
+
+	second, next, err := repo.TagTweets(context.Background(), "golang", "user", &dto.TweetQuery{Size: 2, Cursor: next})
+	assert.NoError(t, err)
+	assert.Len(t, second, 1)
+	assert.Empty(t, next)
+}
+
+func TestMergeTags(t *testing.T) {
+	hashtags := entities.Parse("Aprendiendo #golang y #Redis con #GoLang").Hashtags
+
+	tags, err := mergeTags([]string{"#backend", "redis"}, hashtags)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"backend", "redis", "golang"}, tags)
+
+	_, err = mergeTags([]string{"uno11", "dos22", "tres3", "cuatro"}, entities.Parse("#cinco #seis").Hashtags)
+	assert.ErrorIs(t, err, models.ErrTooManyTags)
+
+	_, err = mergeTags(nil, entities.Parse("#unhashtagdemasiadolargo").Hashtags)
+	assert.ErrorIs(t, err, models.ErrTagTooLong)
+}
+
+func TestRepository_Search(t *testing.T) {
+	repo, db := newTestRepository(t)
+
+	base := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	contents := []struct {
+		content string
+		tags    []string
+	}{
+		{"Un café con leche por la mañana", nil},
+		{"Leche y café, el desayuno perfecto", []string{"desayuno"}},
+		{"Hoy no hay café", []string{"desayuno"}},
+	}
+	for i, c := range contents {
+		tweet := &models.Tweet{UserID: "user", Kind: models.TweetKindOriginal, Content: c.content, CreatedAt: base.AddDate(0, 0, i)}
+		assert.NoError(t, db.Create(tweet).Error)
+		assert.NoError(t, repo.indexTweet(db, tweet, c.tags))
+	}
+
+	// Sin acentos, ordenado por recencia y paginado
+	first, next, err := repo.Search(context.Background(), &dto.SearchQuery{Q: "cafe", Order: models.SearchOrderRecent, Size: 2}, "user")
+	assert.NoError(t, err)
+	assert.Len(t, first, 2)
+	assert.NotEmpty(t, next)
+	assert.Equal(t, contents[2].content, first[0].Content)
+
+	second, next, err := repo.Search(context.Background(), &dto.SearchQuery{Q: "cafe", Order: models.SearchOrderRecent, Size: 2, Cursor: next}, "user")
+	assert.NoError(t, err)
+	assert.Len(t, second, 1)
+	assert.Empty(t, next)
+
+	// Por relevancia se recorren todos los resultados sin repetir
+	seen := make(map[string]bool)
+	query := &dto.SearchQuery{Q: "cafe", Size: 1}
+	for {
+		tweets, next, err := repo.Search(context.Background(), query, "user")
+		assert.NoError(t, err)
+		for _, tweet := range tweets {
+			seen[tweet.ID] = true
+		}
+		if next == "" {
+			break
+		}
+		query.Cursor = next
+	}
+	assert.Len(t, seen, 3)
+
+	// Frase, tag y rango de fechas
+	tweets, _, err := repo.Search(context.Background(), &dto.SearchQuery{Q: `"cafe con leche"`}, "user")
+	assert.NoError(t, err)
+	assert.Len(t, tweets, 1)
+
+	tweets, _, err = repo.Search(context.Background(), &dto.SearchQuery{Q: "#desayuno leche"}, "user")
+	assert.NoError(t, err)
+	if assert.Len(t, tweets, 1) {
+		assert.Equal(t, contents[1].content, tweets[0].Content)
+	}
+
+	tweets, _, err = repo.Search(context.Background(), &dto.SearchQuery{Q: "cafe since:2024-05-11 until:2024-05-12"}, "user")
+	assert.NoError(t, err)
+	assert.Len(t, tweets, 1)
+
+	_, _, err = repo.Search(context.Background(), &dto.SearchQuery{Q: "since:ayer"}, "user")
+	assert.ErrorIs(t, err, models.ErrInvalidQuery)
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tweet-service/internal/domain/models"
+)
+
+const privateUsersKey = "private_users"
+
+// checkBlocked devuelve ErrBlocked si alguno de los dos usuarios bloqueó al
+// otro. Los bloqueos los mantiene el user-service en blocking:<id>.
+func (r *repository) checkBlocked(ctx context.Context, userID, otherID string) error {
+	if userID == otherID {
+		return nil
+	}
+
+	pipe := r.redis.Pipeline()
+	blocking := pipe.SIsMember(ctx, fmt.Sprintf("blocking:%s", userID), otherID)
+	blockedBy := pipe.SIsMember(ctx, fmt.Sprintf("blocking:%s", otherID), userID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("error al verificar los bloqueos: %w", err)
+	}
+
+	if blocking.Val() || blockedBy.Val() {
+		return models.ErrBlocked
+	}
+
+	return nil
+}
+
+// checkVisible devuelve ErrPrivateAccount si authorID tiene la cuenta privada y
+// viewerID no es uno de sus seguidores aprobados. El user-service mantiene las
+// cuentas privadas en private_users y solo agrega a followers:<id> a los
+// seguidores cuya solicitud fue aprobada.
+func (r *repository) checkVisible(ctx context.Context, authorID, viewerID string) error {
+	if authorID == viewerID {
+		return nil
+	}
+
+	pipe := r.redis.Pipeline()
+	private := pipe.SIsMember(ctx, privateUsersKey, authorID)
+	follower := pipe.SIsMember(ctx, fmt.Sprintf("followers:%s", authorID), viewerID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("error al verificar la privacidad de la cuenta: %w", err)
+	}
+
+	if private.Val() && !follower.Val() {
+		return models.ErrPrivateAccount
+	}
+
+	return nil
+}
+
+// filterVisible descarta de tweets los de cuentas privadas que viewerID no
+// puede ver, consultando una sola vez por autor.
+func (r *repository) filterVisible(ctx context.Context, tweets []*models.Tweet, viewerID string) ([]*models.Tweet, error) {
+	visible := make([]*models.Tweet, 0, len(tweets))
+	checked := make(map[string]bool)
+	for _, tweet := range tweets {
+		allowed, ok := checked[tweet.UserID]
+		if !ok {
+			err := r.checkVisible(ctx, tweet.UserID, viewerID)
+			if err != nil && !errors.Is(err, models.ErrPrivateAccount) {
+				return nil, err
+			}
+			allowed = err == nil
+			checked[tweet.UserID] = allowed
+		}
+		if allowed {
+			visible = append(visible, tweet)
+		}
+	}
+
+	return visible, nil
+}
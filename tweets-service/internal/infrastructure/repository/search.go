@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"tweet-service/internal/application/dto"
+	"tweet-service/internal/domain/models"
+	"unicode"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// searchDateLayout es el formato de los operadores since: y until:
+const searchDateLayout = "2006-01-02"
+
+// MigrateSearch crea la tabla virtual FTS5 tweets_fts con el contenido y los
+// tags de cada tweet. Los acentos se ignoran al indexar y al buscar. Si la
+// tabla está vacía se indexan los tweets existentes.
+func MigrateSearch(db *gorm.DB) error {
+	if err := db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS tweets_fts USING fts5(
+		tweet_id UNINDEXED, content, tags, tokenize = 'unicode61 remove_diacritics 2'
+	)`).Error; err != nil {
+		return fmt.Errorf("error al crear el índice de búsqueda: %w", err)
+	}
+
+	var count int64
+	if err := db.Table("tweets_fts").Count(&count).Error; err != nil {
+		return fmt.Errorf("error al verificar el índice de búsqueda: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if err := db.Exec(`INSERT INTO tweets_fts (tweet_id, content, tags)
+		SELECT tweets.id, tweets.content, COALESCE((
+			SELECT group_concat(tags.name, ' ') FROM tweet_tags
+			JOIN tags ON tags.id = tweet_tags.tag_id
+			WHERE tweet_tags.tweet_id = tweets.id
+		), '')
+		FROM tweets WHERE tweets.deleted_at IS NULL AND tweets.kind <> ?`, models.TweetKindRetweet).Error; err != nil {
+		return fmt.Errorf("error al indexar los tweets existentes: %w", err)
+	}
+
+	return nil
+}
+
+// indexTweet agrega el tweet al índice de búsqueda. Los retweets no tienen
+// contenido propio y no se indexan.
+func (r *repository) indexTweet(tx *gorm.DB, tweet *models.Tweet, tags []string) error {
+	if tweet.Kind == models.TweetKindRetweet {
+		return nil
+	}
+
+	if err := tx.Exec("INSERT INTO tweets_fts (tweet_id, content, tags) VALUES (?, ?, ?)",
+		tweet.ID, tweet.Content, strings.Join(tags, " ")).Error; err != nil {
+		return fmt.Errorf("error al indexar el tweet: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) unindexTweet(tx *gorm.DB, id string) error {
+	if err := tx.Exec("DELETE FROM tweets_fts WHERE tweet_id = ?", id).Error; err != nil {
+		return fmt.Errorf("error al retirar el tweet del índice de búsqueda: %w", err)
+	}
+
+	return nil
+}
+
+// searchTerms es una consulta ya interpretada: el texto a buscar en FTS5 y
+// los filtros de los operadores.
+type searchTerms struct {
+	match string
+	from  string
+	since *time.Time
+	until *time.Time
+}
+
+// parseSearch interpreta q. Las palabras sueltas deben aparecer todas, el
+// texto entre comillas se busca como frase, #tag filtra por tag, from:nickname
+// por autor y since:/until: (AAAA-MM-DD) por fecha, con until exclusivo.
+func parseSearch(q string) (*searchTerms, error) {
+	terms := &searchTerms{}
+	var match []string
+
+	for _, token := range splitSearch(q) {
+		if token.phrase {
+			match = append(match, "content : "+quoteFTS(token.text))
+			continue
+		}
+
+		operator, value, found := strings.Cut(token.text, ":")
+		switch {
+		case found && value != "" && operator == "from":
+			terms.from = strings.ToLower(strings.TrimPrefix(value, "@"))
+		case found && value != "" && (operator == "since" || operator == "until"):
+			date, err := time.Parse(searchDateLayout, value)
+			if err != nil {
+				return nil, models.ErrInvalidQuery
+			}
+			if operator == "since" {
+				terms.since = &date
+			} else {
+				terms.until = &date
+			}
+		case strings.HasPrefix(token.text, "#") && len(token.text) > 1:
+			match = append(match, "tags : "+quoteFTS(token.text[1:]))
+		default:
+			match = append(match, "content : "+quoteFTS(token.text))
+		}
+	}
+
+	if len(match) == 0 && terms.from == "" && terms.since == nil && terms.until == nil {
+		return nil, models.ErrInvalidQuery
+	}
+	terms.match = strings.Join(match, " AND ")
+
+	return terms, nil
+}
+
+type searchToken struct {
+	text   string
+	phrase bool
+}
+
+// splitSearch separa q en palabras, respetando las frases entre comillas.
+func splitSearch(q string) []searchToken {
+	var tokens []searchToken
+	var current strings.Builder
+	inPhrase := false
+
+	flush := func(phrase bool) {
+		if text := strings.TrimSpace(current.String()); text != "" {
+			tokens = append(tokens, searchToken{text: text, phrase: phrase})
+		}
+		current.Reset()
+	}
+
+	for _, r := range q {
+		switch {
+		case r == '"':
+			flush(inPhrase)
+			inPhrase = !inPhrase
+		case unicode.IsSpace(r) && !inPhrase:
+			flush(false)
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush(inPhrase)
+
+	return tokens
+}
+
+// quoteFTS escapa el texto como cadena de FTS5 para que los operadores del
+// usuario no se interpreten como sintaxis de la consulta.
+func quoteFTS(text string) string {
+	return `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+}
+
+type searchRow struct {
+	ID        string
+	CreatedAt time.Time
+	Score     float64
+}
+
+// Search busca tweets según query. Sin texto a buscar o con order=recent se
+// ordena del más reciente al más antiguo; si no, por relevancia (bm25). Como
+// en TagTweets, los tweets de cuentas privadas que viewerID no puede ver se
+// omiten de la página.
+func (r *repository) Search(ctx context.Context, query *dto.SearchQuery, viewerID string) ([]*models.Tweet, string, error) {
+	terms, err := parseSearch(query.Q)
+	if err != nil {
+		return nil, "", err
+	}
+
+	size := query.Size
+	if size <= 0 {
+		size = defaultPageSize
+	}
+
+	var db *gorm.DB
+	if terms.match != "" {
+		db = r.db.WithContext(ctx).
+			Table("(SELECT tweet_id, bm25(tweets_fts) AS score FROM tweets_fts WHERE tweets_fts MATCH ?) AS search", terms.match).
+			Select("tweets.id, tweets.created_at, search.score").
+			Joins("JOIN tweets ON tweets.id = search.tweet_id")
+	} else {
+		db = r.db.WithContext(ctx).
+			Table("tweets").
+			Select("tweets.id, tweets.created_at, 0 AS score").
+			Where("tweets.kind <> ?", models.TweetKindRetweet)
+	}
+	db = db.Where("tweets.deleted_at IS NULL")
+
+	if terms.from != "" {
+		userID, err := r.redis.HGet(ctx, nicknamesKey, terms.from).Result()
+		if errors.Is(err, redis.Nil) {
+			return []*models.Tweet{}, "", nil
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("error al resolver el autor: %w", err)
+		}
+		db = db.Where("tweets.user_id = ?", userID)
+	}
+	if terms.since != nil {
+		db = db.Where("tweets.created_at >= ?", *terms.since)
+	}
+	if terms.until != nil {
+		db = db.Where("tweets.created_at < ?", *terms.until)
+	}
+
+	byRelevance := terms.match != "" && query.Order != models.SearchOrderRecent
+	if byRelevance {
+		// bm25 devuelve valores más bajos para los resultados más relevantes
+		if query.Cursor != "" {
+			score, id, err := decodeScoreCursor(query.Cursor)
+			if err != nil {
+				return nil, "", err
+			}
+			db = db.Where("search.score > ? OR (search.score = ? AND tweets.id > ?)", score, score, id)
+		}
+		db = db.Order("search.score ASC, tweets.id ASC")
+	} else {
+		if query.Cursor != "" {
+			createdAt, id, err := decodeCursor(query.Cursor)
+			if err != nil {
+				return nil, "", err
+			}
+			db = db.Where("tweets.created_at < ? OR (tweets.created_at = ? AND tweets.id < ?)", createdAt, createdAt, id)
+		}
+		db = db.Order("tweets.created_at DESC, tweets.id DESC")
+	}
+
+	// Se pide un elemento extra para saber si existe una página siguiente
+	var rows []searchRow
+	if err := db.Limit(size + 1).Scan(&rows).Error; err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, "", fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+		}
+		return nil, "", fmt.Errorf("error al buscar los tweets: %w", err)
+	}
+
+	nextCursor := ""
+	if len(rows) > size {
+		rows = rows[:size]
+		last := rows[size-1]
+		if byRelevance {
+			nextCursor = encodeScoreCursor(last.Score, last.ID)
+		} else {
+			nextCursor = encodeCursor(last.CreatedAt, last.ID)
+		}
+	}
+	if len(rows) == 0 {
+		return []*models.Tweet{}, nextCursor, nil
+	}
+
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	var found []*models.Tweet
+	if err := r.db.WithContext(ctx).Preload("Mentions").Where("id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, "", fmt.Errorf("error al obtener los tweets: %w", err)
+	}
+
+	// Devolver los tweets en el orden de la búsqueda
+	byID := make(map[string]*models.Tweet, len(found))
+	for _, tweet := range found {
+		byID[tweet.ID] = tweet
+	}
+	tweets := make([]*models.Tweet, 0, len(found))
+	for _, id := range ids {
+		if tweet, ok := byID[id]; ok {
+			tweets = append(tweets, tweet)
+		}
+	}
+
+	visible, err := r.filterVisible(ctx, tweets, viewerID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return visible, nextCursor, nil
+}
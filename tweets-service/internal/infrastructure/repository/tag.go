@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+	"tweet-service/internal/application/dto"
+	"tweet-service/internal/domain/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// trendingWindow divide una ventana deslizante en buckets de Redis. Cada
+// bucket es un sorted set trending:<ventana>:<índice> con el número de tweets
+// por tag publicados en ese intervalo.
+type trendingWindow struct {
+	bucket  time.Duration
+	buckets int
+}
+
+var trendingWindows = map[string]trendingWindow{
+	models.TrendingWindowHour: {bucket: time.Minute, buckets: 60},
+	models.TrendingWindowDay:  {bucket: time.Hour, buckets: 24},
+}
+
+const defaultTrendingSize = 10
+
+func trendingKey(window string, index int64) string {
+	return fmt.Sprintf("trending:%s:%d", window, index)
+}
+
+// countTags encola en pipe el ajuste de los contadores de tendencias de cada
+// tag en el bucket de createdAt. Los tweets más antiguos que la ventana no
+// modifican contadores ya caducados.
+func countTags(ctx context.Context, pipe redis.Pipeliner, tags []models.Tag, createdAt time.Time, delta float64) {
+	if len(tags) == 0 {
+		return
+	}
+
+	now := time.Now()
+	for name, window := range trendingWindows {
+		span := window.bucket * time.Duration(window.buckets)
+		if now.Sub(createdAt) >= span {
+			continue
+		}
+
+		key := trendingKey(name, createdAt.UnixNano()/int64(window.bucket))
+		for _, tag := range tags {
+			pipe.ZIncrBy(ctx, key, delta, tag.Name)
+		}
+		// El bucket se conserva mientras forme parte de la ventana
+		pipe.Expire(ctx, key, span+window.bucket)
+	}
+}
+
+// TagTweets lista los tweets con el tag name, del más reciente al más antiguo.
+// Se omiten los tweets de cuentas privadas que viewerID no puede ver, por lo
+// que una página puede tener menos elementos que size.
+func (r *repository) TagTweets(ctx context.Context, name, viewerID string, query *dto.TweetQuery) ([]*models.Tweet, string, error) {
+	size := query.Size
+	if size <= 0 {
+		size = defaultPageSize
+	}
+
+	db := r.db.WithContext(ctx).
+		Preload("Mentions").
+		Joins("JOIN tweet_tags ON tweet_tags.tweet_id = tweets.id").
+		Joins("JOIN tags ON tags.id = tweet_tags.tag_id").
+		Where("tags.name = ?", cleanSpaces(name))
+	if query.Cursor != "" {
+		createdAt, id, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		db = db.Where("tweets.created_at < ? OR (tweets.created_at = ? AND tweets.id < ?)", createdAt, createdAt, id)
+	}
+
+	// Se pide un elemento extra para saber si existe una página siguiente
+	var tweets []*models.Tweet
+	if err := db.Order("tweets.created_at DESC, tweets.id DESC").Limit(size + 1).Find(&tweets).Error; err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, "", fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+		}
+		return nil, "", fmt.Errorf("error al obtener los tweets: %w", err)
+	}
+
+	nextCursor := ""
+	if len(tweets) > size {
+		tweets = tweets[:size]
+		last := tweets[size-1]
+		nextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	// El cursor se calcula antes de filtrar para no saltarse tweets
+	visible, err := r.filterVisible(ctx, tweets, viewerID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return visible, nextCursor, nil
+}
+
+// Trending devuelve los tags más usados en la ventana indicada, sumando los
+// buckets que la componen.
+func (r *repository) Trending(ctx context.Context, window string, size int) ([]*models.TrendingTag, error) {
+	if window == "" {
+		window = models.TrendingWindowHour
+	}
+	w, ok := trendingWindows[window]
+	if !ok {
+		return nil, models.ErrInvalidWindow
+	}
+	if size <= 0 {
+		size = defaultTrendingSize
+	}
+
+	current := time.Now().UnixNano() / int64(w.bucket)
+	keys := make([]string, 0, w.buckets)
+	for i := 0; i < w.buckets; i++ {
+		keys = append(keys, trendingKey(window, current-int64(i)))
+	}
+
+	scores, err := r.redis.ZUnionWithScores(ctx, redis.ZStore{Keys: keys}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener las tendencias: %w", err)
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+
+	trending := make([]*models.TrendingTag, 0, size)
+	for _, z := range scores {
+		if len(trending) == size || z.Score <= 0 {
+			break
+		}
+		trending = append(trending, &models.TrendingTag{
+			Name:  z.Member.(string),
+			Count: int64(z.Score),
+		})
+	}
+
+	return trending, nil
+}
@@ -117,7 +117,7 @@ func (s *Seeder) Seed() {
 
 func (s *Seeder) Clean() {
 	ctx := context.Background()
-	for _, table := range []string{"tweet_tags", "comments", "tags", "tweets"} {
+	for _, table := range []string{"tweet_tags", "tweet_likes", "tweet_deletions", "mentions", "tweets_fts", "comments", "tags", "tweets"} {
 		// Eliminar contenido de cada tabla
 		err := s.db.Exec("DELETE FROM " + table).Error
 		if err != nil {
@@ -128,6 +128,18 @@ func (s *Seeder) Clean() {
 	if err := deleteKeysWithPrefix(ctx, s.redis, "tweets:"); err != nil {
 		fmt.Printf("Error al borrar claves con prefijo tweets:: %v\n", err)
 	}
+
+	if err := deleteKeysWithPrefix(ctx, s.redis, "user_tweets:"); err != nil {
+		fmt.Printf("Error al borrar claves con prefijo user_tweets:: %v\n", err)
+	}
+
+	if err := deleteKeysWithPrefix(ctx, s.redis, "trending:"); err != nil {
+		fmt.Printf("Error al borrar claves con prefijo trending:: %v\n", err)
+	}
+
+	if err := deleteKeysWithPrefix(ctx, s.redis, "affinity:"); err != nil {
+		fmt.Printf("Error al borrar claves con prefijo affinity:: %v\n", err)
+	}
 }
 
 func deleteKeysWithPrefix(ctx context.Context, rdb *redis.Client, prefix string) error {
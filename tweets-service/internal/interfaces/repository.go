@@ -8,5 +8,18 @@ import (
 
 type TweetRepository interface {
 	Create(ctx context.Context, tweet *dto.CreateTweet) (*models.Tweet, error)
-	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id, userID string) (*models.Tweet, error)
+	UserTweets(ctx context.Context, userID, viewerID string, query *dto.TweetQuery) ([]*models.Tweet, string, error)
+	Delete(ctx context.Context, id, userID string, admin bool) error
+	Retweet(ctx context.Context, id, userID string) (*models.Tweet, error)
+	Quote(ctx context.Context, id, userID string, quote *dto.CreateQuote) (*models.Tweet, error)
+	Like(ctx context.Context, id, userID string) (*models.Tweet, error)
+	Unlike(ctx context.Context, id, userID string) (*models.Tweet, error)
+	CreateComment(ctx context.Context, tweetID, userID string, comment *dto.CreateComment) (*models.Comment, error)
+	Comments(ctx context.Context, tweetID, viewerID string, query *dto.CommentQuery) ([]*models.Comment, string, error)
+	DeleteComment(ctx context.Context, id, userID string) error
+	TagTweets(ctx context.Context, name, viewerID string, query *dto.TweetQuery) ([]*models.Tweet, string, error)
+	Trending(ctx context.Context, window string, size int) ([]*models.TrendingTag, error)
+	Search(ctx context.Context, query *dto.SearchQuery, viewerID string) ([]*models.Tweet, string, error)
+	SyncCache(ctx context.Context) error
 }
@@ -7,5 +7,17 @@ import (
 
 type Tweetservice interface {
 	Create(ctx context.Context, tweet *dto.CreateTweet) (*dto.Tweet, error)
-	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id, userID string) (*dto.Tweet, error)
+	UserTweets(ctx context.Context, userID, viewerID string, query *dto.TweetQuery) (*dto.TweetPage, error)
+	Delete(ctx context.Context, id, userID string, admin bool) error
+	Retweet(ctx context.Context, id, userID string) (*dto.Tweet, error)
+	Quote(ctx context.Context, id, userID string, quote *dto.CreateQuote) (*dto.Tweet, error)
+	Like(ctx context.Context, id, userID string) (*dto.Tweet, error)
+	Unlike(ctx context.Context, id, userID string) (*dto.Tweet, error)
+	CreateComment(ctx context.Context, tweetID, userID string, comment *dto.CreateComment) (*dto.Comment, error)
+	Comments(ctx context.Context, tweetID, viewerID string, query *dto.CommentQuery) (*dto.CommentPage, error)
+	DeleteComment(ctx context.Context, id, userID string) error
+	TagTweets(ctx context.Context, name, viewerID string, query *dto.TweetQuery) (*dto.TweetPage, error)
+	Trending(ctx context.Context, query *dto.TrendingQuery) ([]dto.TrendingTag, error)
+	Search(ctx context.Context, query *dto.SearchQuery, viewerID string) (*dto.TweetPage, error)
 }
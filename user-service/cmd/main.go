@@ -1,8 +1,13 @@
 package main
 
 import (
+	"context"
+	"log"
+	sharedauth "shared/auth"
 	"user_service/config"
 	"user_service/internal/application"
+	"user_service/internal/infrastructure/auth"
+	"user_service/internal/infrastructure/cron"
 	"user_service/internal/infrastructure/http"
 	"user_service/internal/infrastructure/repository"
 	"user_service/internal/infrastructure/seeder"
@@ -29,9 +34,24 @@ func main() {
 		seed.Seed()
 	}
 
+	// Completar en Redis los índices de los usuarios creados antes de existir
+	if err := repo.SyncCache(context.Background()); err != nil {
+		log.Printf("Error al sincronizar los usuarios en Redis: %v", err)
+	}
+
+	issuer, err := auth.NewIssuer(cfg.Auth, redis)
+	if err != nil {
+		log.Fatalf("Error al configurar la autenticación: %v", err)
+	}
+
+	// Recalcular las sugerencias cuando cambia el grafo de seguimiento
+	process := cron.NewCron(redis, repo)
+	go process.ProcessFollows()
+
 	service := application.NewService(repo)
+	authService := application.NewAuthService(repo, issuer)
 
-	httpServer := http.NewHTTPServer(engine, service, validate)
+	httpServer := http.NewHTTPServer(engine, service, authService, validate, sharedauth.Middleware(issuer.Verifier, cfg.Auth.LegacyHeader))
 	httpServer.Run(cfg.Port)
 }
 
@@ -3,6 +3,8 @@ package config
 import (
 	"context"
 	"log"
+	"shared/auth"
+	"time"
 	"user_service/internal/domain/models"
 
 	"github.com/glebarez/sqlite"
@@ -17,6 +19,19 @@ type Config struct {
 	SqlitePath   string
 	Env          string
 	RedisOptions *redis.Options
+	Auth         AuthConfig
+}
+
+// AuthConfig controla la emisión y verificación de los JWT. Con HS256 se usa
+// Secret; con EdDSA, las claves Ed25519 en formato PEM. Los secretos se leen
+// de las variables AUTH_SECRET, AUTH_PRIVATE_KEY y AUTH_PUBLIC_KEY.
+type AuthConfig struct {
+	auth.Config
+	PrivateKey string
+	AccessTTL  time.Duration
+	SessionTTL time.Duration
+	// LegacyHeader acepta el header User-ID sin token, solo para desarrollo local
+	LegacyHeader bool
 }
 
 func LoadConfig() *Config {
@@ -24,6 +39,15 @@ func LoadConfig() *Config {
 	viper.SetConfigType("yml")
 	viper.AddConfigPath(".")
 
+	viper.SetDefault("auth.algorithm", "HS256")
+	viper.SetDefault("auth.issuer", "user-service")
+	viper.SetDefault("auth.access_ttl", "15m")
+	viper.SetDefault("auth.session_ttl", "720h")
+	viper.SetDefault("auth.legacy_header", false)
+	viper.BindEnv("auth.secret", "AUTH_SECRET")
+	viper.BindEnv("auth.private_key", "AUTH_PRIVATE_KEY")
+	viper.BindEnv("auth.public_key", "AUTH_PUBLIC_KEY")
+
 	if err := viper.ReadInConfig(); err != nil {
 		log.Fatalf("Error al leer la configuración: %v", err)
 	}
@@ -38,6 +62,18 @@ func LoadConfig() *Config {
 			Password: viper.GetString("db.redis.password"),
 			DB:       viper.GetInt("db.redis.db"),
 		},
+		Auth: AuthConfig{
+			Config: auth.Config{
+				Algorithm: viper.GetString("auth.algorithm"),
+				Secret:    viper.GetString("auth.secret"),
+				PublicKey: viper.GetString("auth.public_key"),
+				Issuer:    viper.GetString("auth.issuer"),
+			},
+			PrivateKey:   viper.GetString("auth.private_key"),
+			AccessTTL:    viper.GetDuration("auth.access_ttl"),
+			SessionTTL:   viper.GetDuration("auth.session_ttl"),
+			LegacyHeader: viper.GetBool("auth.legacy_header"),
+		},
 	}
 }
 
@@ -48,7 +84,7 @@ func (c *Config) Sqlite() *gorm.DB {
 	}
 
 	// Migrar los modelos para crear tablas automáticamente
-	if err := db.AutoMigrate(&models.User{}, &models.Follower{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.Follower{}, &models.Block{}, &models.Mute{}, &models.FollowRequest{}); err != nil {
 		log.Fatalf("Error al migrar las tablas: %v", err)
 	}
 	db.Exec("PRAGMA foreign_keys = ON;")
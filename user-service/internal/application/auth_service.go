@@ -0,0 +1,156 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	sharedauth "shared/auth"
+	"time"
+	"user_service/internal/application/dto"
+	"user_service/internal/domain/models"
+	"user_service/internal/infrastructure/auth"
+	"user_service/internal/interfaces"
+
+	"github.com/jinzhu/copier"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type authService struct {
+	repo   interfaces.UserRepository
+	issuer *auth.Issuer
+}
+
+func NewAuthService(repo interfaces.UserRepository, issuer *auth.Issuer) interfaces.AuthService {
+	return &authService{
+		repo:   repo,
+		issuer: issuer,
+	}
+}
+
+func (s *authService) Register(ctx context.Context, register *dto.Register) (*dto.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(register.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("error al procesar la contraseña: %w", err)
+	}
+
+	user := register.CreateUser
+	user.PasswordHash = string(hash)
+
+	newUser, err := s.repo.Create(ctx, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	userDTO := &dto.User{}
+	if err := copier.Copy(userDTO, newUser); err != nil {
+		return nil, err
+	}
+
+	return userDTO, nil
+}
+
+func (s *authService) Login(ctx context.Context, login *dto.Login) (*dto.Token, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	user, err := s.repo.GetByNickname(ctx, login.Nickname)
+	if err != nil {
+		// No revelar si el usuario existe
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, models.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if user.PasswordHash == "" ||
+		bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(login.Password)) != nil {
+		return nil, models.ErrInvalidCredentials
+	}
+
+	// El token de sesión es opaco; Redis solo guarda su ID
+	sessionToken, sessionID, err := auth.NewSessionToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.Session{ID: sessionID, UserID: user.ID, Device: login.Device}
+	if err := s.repo.CreateSession(ctx, session, s.issuer.SessionTTL()); err != nil {
+		return nil, err
+	}
+
+	return s.issue(user.ID, sessionID, sessionToken)
+}
+
+// Refresh emite un nuevo token de acceso para la sesión y renueva su expiración.
+func (s *authService) Refresh(ctx context.Context, refresh *dto.Refresh) (*dto.Token, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	session, err := s.repo.GetSession(ctx, auth.SessionID(refresh.RefreshToken))
+	if err != nil {
+		if errors.Is(err, models.ErrSessionNotFound) {
+			return nil, sharedauth.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if err := s.repo.TouchSession(ctx, session.ID, s.issuer.SessionTTL()); err != nil {
+		if errors.Is(err, models.ErrSessionNotFound) {
+			return nil, sharedauth.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return s.issue(session.UserID, session.ID, refresh.RefreshToken)
+}
+
+func (s *authService) Sessions(ctx context.Context, userID, currentID string) ([]dto.Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	sessions, err := s.repo.Sessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionDTOs := make([]dto.Session, 0, len(sessions))
+	if err := copier.Copy(&sessionDTOs, sessions); err != nil {
+		return nil, err
+	}
+	for i := range sessionDTOs {
+		sessionDTOs[i].Current = sessionDTOs[i].ID == currentID
+	}
+
+	return sessionDTOs, nil
+}
+
+func (s *authService) RevokeSession(ctx context.Context, userID, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	return s.repo.RevokeSession(ctx, userID, id)
+}
+
+func (s *authService) RevokeSessions(ctx context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	return s.repo.RevokeSessions(ctx, userID)
+}
+
+func (s *authService) issue(userID, sessionID, sessionToken string) (*dto.Token, error) {
+	accessToken, err := s.issuer.Issue(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.Token{
+		AccessToken:  accessToken,
+		RefreshToken: sessionToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.issuer.AccessTTL().Seconds()),
+	}, nil
+}
@@ -0,0 +1,95 @@
+package application
+
+import (
+	"context"
+	sharedauth "shared/auth"
+	"testing"
+	"time"
+	"user_service/config"
+	"user_service/internal/application/dto"
+	"user_service/internal/domain/models"
+	"user_service/internal/infrastructure/auth"
+	"user_service/internal/mocks"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestIssuer(t *testing.T) *auth.Issuer {
+	issuer, err := auth.NewIssuer(config.AuthConfig{
+		Config: sharedauth.Config{
+			Algorithm: "HS256",
+			Secret:    "secreto",
+			Issuer:    "user-service",
+		},
+		AccessTTL:  time.Minute,
+		SessionTTL: time.Hour,
+	}, redis.NewClient(&redis.Options{}))
+	if err != nil {
+		t.Fatalf("Failed to create issuer: %v", err)
+	}
+	return issuer
+}
+
+func TestAuthService_Register_HashesPassword(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	service := NewAuthService(mockRepo, newTestIssuer(t))
+
+	input := &dto.Register{
+		CreateUser: dto.CreateUser{Name: "Test User", Email: "test@example.com", Nickname: "testuser"},
+		Password:   "contraseña",
+	}
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(user *dto.CreateUser) bool {
+		return bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password)) == nil
+	})).Return(&models.User{ID: "12345"}, nil)
+
+	result, err := service.Register(context.Background(), input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", result.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Login(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	issuer := newTestIssuer(t)
+	service := NewAuthService(mockRepo, issuer)
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("contraseña"), bcrypt.MinCost)
+	mockRepo.On("GetByNickname", mock.Anything, "testuser").Return(&models.User{ID: "12345", PasswordHash: string(hash)}, nil)
+
+	var session *models.Session
+	mockRepo.On("CreateSession", mock.Anything, mock.Anything, time.Hour).Run(func(args mock.Arguments) {
+		session = args.Get(1).(*models.Session)
+	}).Return(nil)
+
+	// Contraseña incorrecta
+	_, err := service.Login(context.Background(), &dto.Login{Nickname: "testuser", Password: "otra"})
+	assert.ErrorIs(t, err, models.ErrInvalidCredentials)
+
+	token, err := service.Login(context.Background(), &dto.Login{Nickname: "testuser", Password: "contraseña", Device: "test"})
+	assert.NoError(t, err)
+
+	// El token de sesión identifica a la sesión creada sin guardarse en ella
+	assert.Equal(t, "12345", session.UserID)
+	assert.Equal(t, "test", session.Device)
+	assert.Equal(t, session.ID, auth.SessionID(token.RefreshToken))
+
+	claims, err := issuer.Parse(token.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", claims.Subject)
+	assert.Equal(t, session.ID, claims.SessionID)
+}
+
+func TestAuthService_Refresh_RevokedSession(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	service := NewAuthService(mockRepo, newTestIssuer(t))
+
+	mockRepo.On("GetSession", mock.Anything, auth.SessionID("revocado")).Return(nil, models.ErrSessionNotFound)
+
+	_, err := service.Refresh(context.Background(), &dto.Refresh{RefreshToken: "revocado"})
+	assert.ErrorIs(t, err, sharedauth.ErrInvalidToken)
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+type Register struct {
+	CreateUser
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type Login struct {
+	Nickname string `json:"nickname" validate:"required"`
+	Password string `json:"password" validate:"required"`
+	// Device describe el dispositivo de la sesión; por defecto el User-Agent
+	Device string `json:"device" validate:"max=200"`
+}
+
+type Refresh struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type Token struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	TokenType    string `json:"tokenType"`
+	ExpiresIn    int    `json:"expiresIn"`
+}
+
+type Session struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	Current    bool      `json:"current"`
+}
@@ -9,6 +9,7 @@ type User struct {
 	Avatar    string `json:"avatar"`
 	Followers int    `json:"followers"`
 	Following int    `json:"following"`
+	Private   bool   `json:"private"`
 }
 
 type Follower struct {
@@ -24,6 +25,9 @@ type CreateUser struct {
 	Nickname string `json:"nickname" validate:"required,alphanum,min=3,max=30"`
 	Bio      string `json:"bio" validate:"max=500"`
 	Avatar   string `json:"avatar" validate:"omitempty,url"`
+
+	// PasswordHash lo establece el servicio de autenticación al registrar
+	PasswordHash string `json:"-"`
 }
 
 type UpdateUser struct {
@@ -31,4 +35,41 @@ type UpdateUser struct {
 	Nickname string `json:"nickname" validate:"omitempty,alphanum,min=3,max=30"`
 	Bio      string `json:"bio" validate:"omitempty,max=500"`
 	Avatar   string `json:"avatar" validate:"omitempty,url"`
+	// Private es un puntero para distinguir "false" de un campo no enviado
+	Private *bool `json:"private"`
+}
+
+type FollowQuery struct {
+	Cursor string `form:"cursor"`
+	Size   int    `form:"size" validate:"omitempty,min=1,max=100"`
+}
+
+// UserSearchQuery busca por prefijo del nickname o del nombre, sin distinguir
+// mayúsculas ni acentos.
+type UserSearchQuery struct {
+	Q    string `form:"q" validate:"required,max=50"`
+	Size int    `form:"size" validate:"omitempty,min=1,max=20"`
+}
+
+type FollowerPage struct {
+	Users      []Follower `json:"users"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
+
+type SuggestionQuery struct {
+	Size int `form:"size" validate:"omitempty,min=1,max=50"`
+}
+
+// Suggestion es un usuario sugerido junto con la cantidad de usuarios seguidos
+// que ya lo siguen.
+type Suggestion struct {
+	Follower
+	Mutuals int `json:"mutuals"`
+}
+
+type Relationship struct {
+	UserID     string `json:"userId"`
+	OtherID    string `json:"otherId"`
+	Following  bool   `json:"following"`
+	FollowedBy bool   `json:"followedBy"`
 }
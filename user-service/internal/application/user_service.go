@@ -19,24 +19,66 @@ func NewService(repo interfaces.UserRepository) interfaces.UserService {
 	}
 }
 
-func (s *userService) Create(ctx context.Context, user *dto.CreateUser) (*dto.User, error) {
+func (s *userService) GetById(ctx context.Context, id string) (*dto.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
-	newUser, err := s.repo.Create(ctx, user)
+	user, err := s.repo.GetById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	userDTO := &dto.User{}
-	if err := copier.Copy(userDTO, newUser); err != nil {
+	if err := copier.Copy(userDTO, user); err != nil {
 		return nil, err
 	}
 
 	return userDTO, nil
 }
 
-func (s *userService) Follow(ctx context.Context, id, followerID string) error {
+func (s *userService) GetByNickname(ctx context.Context, nickname string) (*dto.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	user, err := s.repo.GetByNickname(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+
+	userDTO := &dto.User{}
+	if err := copier.Copy(userDTO, user); err != nil {
+		return nil, err
+	}
+
+	return userDTO, nil
+}
+
+func (s *userService) Update(ctx context.Context, id string, user *dto.UpdateUser) (*dto.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	updatedUser, err := s.repo.Update(ctx, id, user)
+	if err != nil {
+		return nil, err
+	}
+
+	userDTO := &dto.User{}
+	if err := copier.Copy(userDTO, updatedUser); err != nil {
+		return nil, err
+	}
+
+	return userDTO, nil
+}
+
+func (s *userService) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	return s.repo.Delete(ctx, id)
+}
+
+// Follow devuelve true si la cuenta es privada y la solicitud queda pendiente.
+func (s *userService) Follow(ctx context.Context, id, followerID string) (bool, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
@@ -49,3 +91,150 @@ func (s *userService) Unfollow(ctx context.Context, id, followerID string) error
 
 	return s.repo.Unfollow(ctx, id, followerID)
 }
+
+func (s *userService) Search(ctx context.Context, query *dto.UserSearchQuery) ([]dto.Follower, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	users, err := s.repo.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []dto.Follower{}
+	if err := copier.Copy(&result, users); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *userService) Followers(ctx context.Context, id string, query *dto.FollowQuery) (*dto.FollowerPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	users, nextCursor, err := s.repo.Followers(ctx, id, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.FollowerPage{Users: []dto.Follower{}, NextCursor: nextCursor}
+	if err := copier.Copy(&page.Users, users); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (s *userService) Following(ctx context.Context, id string, query *dto.FollowQuery) (*dto.FollowerPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	users, nextCursor, err := s.repo.Following(ctx, id, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.FollowerPage{Users: []dto.Follower{}, NextCursor: nextCursor}
+	if err := copier.Copy(&page.Users, users); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (s *userService) FollowRequests(ctx context.Context, id string, query *dto.FollowQuery) (*dto.FollowerPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	users, nextCursor, err := s.repo.FollowRequests(ctx, id, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.FollowerPage{Users: []dto.Follower{}, NextCursor: nextCursor}
+	if err := copier.Copy(&page.Users, users); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (s *userService) ApproveFollowRequest(ctx context.Context, id, followerID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	return s.repo.ApproveFollowRequest(ctx, id, followerID)
+}
+
+func (s *userService) RejectFollowRequest(ctx context.Context, id, followerID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	return s.repo.RejectFollowRequest(ctx, id, followerID)
+}
+
+func (s *userService) Suggestions(ctx context.Context, id string, query *dto.SuggestionQuery) ([]dto.Suggestion, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	suggestions, err := s.repo.Suggestions(ctx, id, query.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.Suggestion, len(suggestions))
+	for i, suggestion := range suggestions {
+		if err := copier.Copy(&result[i].Follower, suggestion.User); err != nil {
+			return nil, err
+		}
+		result[i].Mutuals = suggestion.Mutuals
+	}
+
+	return result, nil
+}
+
+func (s *userService) Relationship(ctx context.Context, id, otherID string) (*dto.Relationship, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	following, followedBy, err := s.repo.Relationship(ctx, id, otherID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.Relationship{
+		UserID:     id,
+		OtherID:    otherID,
+		Following:  following,
+		FollowedBy: followedBy,
+	}, nil
+}
+
+func (s *userService) Block(ctx context.Context, id, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	return s.repo.Block(ctx, id, userID)
+}
+
+func (s *userService) Unblock(ctx context.Context, id, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	return s.repo.Unblock(ctx, id, userID)
+}
+
+func (s *userService) Mute(ctx context.Context, id, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	return s.repo.Mute(ctx, id, userID)
+}
+
+func (s *userService) Unmute(ctx context.Context, id, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	return s.repo.Unmute(ctx, id, userID)
+}
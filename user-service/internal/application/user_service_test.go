@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"testing"
-	"user_service/internal/application/dto"
 	"user_service/internal/domain/models"
 	"user_service/internal/mocks"
 
@@ -12,61 +11,45 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-func TestUserService_Create(t *testing.T) {
+func TestUserService_GetById(t *testing.T) {
 	// Crear un mock del UserRepository
 	mockRepo := new(mocks.UserRepository)
 	service := NewService(mockRepo)
 
-	// Definir el input
-	input := &dto.CreateUser{
-		Name:     "Test User",
-		Email:    "test@example.com",
-		Nickname: "@testuser",
-		Bio:      "Test bio",
-		Avatar:   "https://example.com/avatar.png",
-	}
-
-	// Definir el output esperado
-	createdUser := &models.User{
+	// Definir el usuario que devuelve el repositorio
+	user := &models.User{
 		ID:        "12345",
-		Name:      input.Name,
-		Email:     input.Email,
-		Nickname:  input.Nickname,
-		Bio:       input.Bio,
-		Avatar:    input.Avatar,
+		Name:      "Test User",
+		Email:     "test@example.com",
+		Nickname:  "@testuser",
+		Bio:       "Test bio",
+		Avatar:    "https://example.com/avatar.png",
 		Followers: 0,
 		Following: 0,
 	}
 
-	// Configurar el mock para esperar la llamada a Create y devolver el usuario creado
-	mockRepo.On("Create", mock.Anything, input).Return(createdUser, nil)
+	// Configurar el mock para esperar la llamada a GetById y devolver el usuario
+	mockRepo.On("GetById", mock.Anything, user.ID).Return(user, nil)
 
 	// Ejecutar el método a probar
-	result, err := service.Create(context.Background(), input)
+	result, err := service.GetById(context.Background(), user.ID)
 
 	// Aserciones
 	assert.NoError(t, err)
-	assert.Equal(t, createdUser.ID, result.ID)
+	assert.Equal(t, user.ID, result.ID)
+	assert.Equal(t, user.Nickname, result.Nickname)
 
 	// Verificar que el mock fue llamado como se esperaba
 	mockRepo.AssertExpectations(t)
 }
 
-func TestUserService_Create_RepositoryError(t *testing.T) {
+func TestUserService_GetById_RepositoryError(t *testing.T) {
 	mockRepo := new(mocks.UserRepository)
 	service := NewService(mockRepo)
 
-	input := &dto.CreateUser{
-		Name:     "Test User",
-		Email:    "test@example.com",
-		Nickname: "@testuser",
-		Bio:      "Test bio",
-		Avatar:   "https://example.com/avatar.png",
-	}
-
-	mockRepo.On("Create", mock.Anything, input).Return(nil, errors.New("database error"))
+	mockRepo.On("GetById", mock.Anything, "12345").Return(nil, errors.New("database error"))
 
-	result, err := service.Create(context.Background(), input)
+	result, err := service.GetById(context.Background(), "12345")
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -0,0 +1,17 @@
+package models
+
+import "errors"
+
+var (
+	ErrUserNotFound  = errors.New("usuario no encontrado")
+	ErrNicknameTaken = errors.New("el nickname ya está en uso")
+	ErrInvalidCursor = errors.New("cursor inválido")
+	ErrSelfAction    = errors.New("un usuario no puede realizar esta acción sobre sí mismo")
+	ErrBlocked       = errors.New("existe un bloqueo entre los usuarios")
+
+	ErrAlreadyFollowing      = errors.New("el usuario ya sigue a este usuario")
+	ErrFollowRequestNotFound = errors.New("solicitud de seguimiento no encontrada")
+
+	ErrInvalidCredentials = errors.New("credenciales inválidas")
+	ErrSessionNotFound    = errors.New("sesión no encontrada")
+)
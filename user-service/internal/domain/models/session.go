@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Session es una sesión iniciada por un usuario desde un dispositivo. Se
+// guarda en el Redis compartido para que todos los servicios la consulten.
+type Session struct {
+	ID         string
+	UserID     string
+	Device     string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
@@ -0,0 +1,8 @@
+package models
+
+// Suggestion es un usuario sugerido para seguir. Mutuals cuenta cuántos de los
+// usuarios seguidos ya lo siguen.
+type Suggestion struct {
+	User    *User
+	Mutuals int
+}
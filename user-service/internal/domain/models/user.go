@@ -18,6 +18,13 @@ type User struct {
 	Following int       `gorm:"default:0"`
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	// PasswordHash es el hash bcrypt de la contraseña; vacío en usuarios sin credenciales
+	PasswordHash string `gorm:"type:text"`
+	// Private exige aprobar cada solicitud de seguimiento
+	Private bool `gorm:"default:false"`
+	// SearchName es el nombre y el nickname normalizados para buscar sin acentos
+	SearchName string `gorm:"index"`
 }
 
 func (tag *User) BeforeCreate(tx *gorm.DB) (err error) {
@@ -28,9 +35,10 @@ func (tag *User) BeforeCreate(tx *gorm.DB) (err error) {
 }
 
 type Follower struct {
-	ID         string `gorm:"primaryKey"`
-	UserID     string `gorm:"index;not null"`
-	FollowerID string `gorm:"index;not null"`
+	ID         string    `gorm:"primaryKey"`
+	UserID     string    `gorm:"index;not null"`
+	FollowerID string    `gorm:"index;not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
 }
 
 func (tag *Follower) BeforeCreate(tx *gorm.DB) (err error) {
@@ -39,3 +47,49 @@ func (tag *Follower) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return
 }
+
+// FollowRequest es una solicitud pendiente de FollowerID para seguir a la
+// cuenta privada UserID.
+type FollowRequest struct {
+	ID         string    `gorm:"primaryKey"`
+	UserID     string    `gorm:"not null;uniqueIndex:idx_follow_request_user"`
+	FollowerID string    `gorm:"not null;uniqueIndex:idx_follow_request_user;index"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+func (tag *FollowRequest) BeforeCreate(tx *gorm.DB) (err error) {
+	if tag.ID == "" {
+		tag.ID = uuid.New().String()
+	}
+	return
+}
+
+// Block impide cualquier interacción entre UserID y BlockedID.
+type Block struct {
+	ID        string    `gorm:"primaryKey"`
+	UserID    string    `gorm:"not null;uniqueIndex:idx_block_user"`
+	BlockedID string    `gorm:"not null;uniqueIndex:idx_block_user;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (tag *Block) BeforeCreate(tx *gorm.DB) (err error) {
+	if tag.ID == "" {
+		tag.ID = uuid.New().String()
+	}
+	return
+}
+
+// Mute oculta a UserID los tweets de MutedID sin que este lo sepa.
+type Mute struct {
+	ID        string    `gorm:"primaryKey"`
+	UserID    string    `gorm:"not null;uniqueIndex:idx_mute_user"`
+	MutedID   string    `gorm:"not null;uniqueIndex:idx_mute_user"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (tag *Mute) BeforeCreate(tx *gorm.DB) (err error) {
+	if tag.ID == "" {
+		tag.ID = uuid.New().String()
+	}
+	return
+}
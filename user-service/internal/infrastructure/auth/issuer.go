@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"fmt"
+	"shared/auth"
+	"time"
+	"user_service/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Issuer firma los tokens de acceso de las sesiones de los usuarios.
+type Issuer struct {
+	*auth.Verifier
+	method     jwt.SigningMethod
+	key        interface{}
+	issuer     string
+	accessTTL  time.Duration
+	sessionTTL time.Duration
+}
+
+func NewIssuer(cfg config.AuthConfig, redis *redis.Client) (*Issuer, error) {
+	verifier, err := auth.NewVerifier(cfg.Config, redis)
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := &Issuer{
+		Verifier:   verifier,
+		issuer:     cfg.Issuer,
+		accessTTL:  cfg.AccessTTL,
+		sessionTTL: cfg.SessionTTL,
+	}
+
+	// NewVerifier ya rechazó los algoritmos no soportados.
+	if cfg.Algorithm == "EdDSA" {
+		key, err := jwt.ParseEdPrivateKeyFromPEM([]byte(cfg.PrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("error al leer auth.private_key: %w", err)
+		}
+		issuer.method = jwt.SigningMethodEdDSA
+		issuer.key = key
+	} else {
+		issuer.method = jwt.SigningMethodHS256
+		issuer.key = []byte(cfg.Secret)
+	}
+
+	return issuer, nil
+}
+
+// Issue genera un token de acceso para el usuario dentro de la sesión indicada.
+func (i *Issuer) Issue(userID, sessionID string) (string, error) {
+	now := time.Now()
+
+	claims := &auth.Claims{
+		Type:      auth.AccessToken,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    i.issuer,
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(i.accessTTL)),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(i.method, claims).SignedString(i.key)
+	if err != nil {
+		return "", fmt.Errorf("error al firmar el token: %w", err)
+	}
+
+	return token, nil
+}
+
+// AccessTTL es la duración de los tokens de acceso.
+func (i *Issuer) AccessTTL() time.Duration {
+	return i.accessTTL
+}
+
+// SessionTTL es el tiempo que una sesión sigue activa sin renovarse.
+func (i *Issuer) SessionTTL() time.Duration {
+	return i.sessionTTL
+}
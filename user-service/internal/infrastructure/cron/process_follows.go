@@ -0,0 +1,82 @@
+package cron
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+	"user_service/internal/interfaces"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// followStream es el stream donde el repositorio publica los cambios de seguimiento
+	followStream = "follow_stream"
+	// consumerGroup es independiente del grupo del timeline-service, por lo que
+	// ambos servicios reciben todos los eventos
+	consumerGroup = "user-service"
+	consumer      = "suggestions"
+)
+
+type cron struct {
+	redis *redis.Client
+	repo  interfaces.UserRepository
+}
+
+func NewCron(redis *redis.Client, repo interfaces.UserRepository) interfaces.Cron {
+	return &cron{redis: redis, repo: repo}
+}
+
+// ProcessFollows recalcula las sugerencias del seguidor cada vez que empieza
+// o deja de seguir a alguien. Los eventos no se confirman: las sugerencias
+// caducan igualmente, por lo que perder un evento solo retrasa su actualización.
+func (c *cron) ProcessFollows() {
+	ctx := context.Background()
+
+	err := c.redis.XGroupCreateMkStream(ctx, followStream, consumerGroup, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		log.Fatalf("Error al crear el grupo de consumidores: %v", err)
+	}
+
+	for {
+		streams, err := c.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    consumerGroup,
+			Consumer: consumer,
+			Streams:  []string{followStream, ">"},
+			Count:    10,
+			Block:    2 * time.Second,
+			NoAck:    true,
+		}).Result()
+		if err != nil {
+			if err != redis.Nil {
+				log.Printf("Error al leer del stream de seguimientos: %v", err)
+				time.Sleep(1 * time.Second)
+			}
+			continue
+		}
+
+		// Varios eventos del mismo seguidor se resuelven con un solo cálculo
+		followers := make(map[string]struct{})
+		for _, stream := range streams {
+			for _, entry := range stream.Messages {
+				if followerID, ok := entry.Values["follower_id"].(string); ok {
+					followers[followerID] = struct{}{}
+				}
+			}
+		}
+
+		for followerID := range followers {
+			c.refreshSuggestions(ctx, followerID)
+		}
+	}
+}
+
+func (c *cron) refreshSuggestions(ctx context.Context, followerID string) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := c.repo.RefreshSuggestions(ctx, followerID); err != nil {
+		log.Printf("Error al recalcular las sugerencias de %s: %v", followerID, err)
+	}
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"shared/auth"
+	"user_service/internal/application/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (s *HTTPServer) register(c *gin.Context) {
+	var register dto.Register
+
+	if err := c.ShouldBindJSON(&register); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(register); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	user, err := s.authService.Register(c.Request.Context(), &register)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+func (s *HTTPServer) login(c *gin.Context) {
+	var login dto.Login
+
+	if err := c.ShouldBindJSON(&login); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(login); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	if login.Device == "" {
+		login.Device = c.GetHeader("User-Agent")
+	}
+
+	token, err := s.authService.Login(c.Request.Context(), &login)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+func (s *HTTPServer) refresh(c *gin.Context) {
+	var refresh dto.Refresh
+
+	if err := c.ShouldBindJSON(&refresh); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(refresh); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	token, err := s.authService.Refresh(c.Request.Context(), &refresh)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+func (s *HTTPServer) sessions(c *gin.Context) {
+	sessions, err := s.authService.Sessions(c.Request.Context(), c.GetString(auth.UserIDKey), c.GetString(auth.SessionIDKey))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+func (s *HTTPServer) revokeSession(c *gin.Context) {
+	if err := s.authService.RevokeSession(c.Request.Context(), c.GetString(auth.UserIDKey), c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sesión revocada correctamente"})
+}
+
+func (s *HTTPServer) revokeSessions(c *gin.Context) {
+	if err := s.authService.RevokeSessions(c.Request.Context(), c.GetString(auth.UserIDKey)); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sesiones revocadas correctamente"})
+}
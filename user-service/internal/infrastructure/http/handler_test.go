@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"shared/auth"
 	"testing"
 	"user_service/internal/application/dto"
+	"user_service/internal/domain/models"
 	"user_service/internal/mocks"
 
 	"github.com/gin-gonic/gin"
@@ -20,20 +22,23 @@ func TestHTTPServer_CreateUser(t *testing.T) {
 	// Configurar Gin en modo test
 	gin.SetMode(gin.TestMode)
 
-	// Crear un mock del UserService
-	mockService := new(mocks.UserService)
+	// Crear un mock del AuthService, que registra los usuarios con contraseña
+	mockAuth := new(mocks.AuthService)
 	validate := validator.New()
 
 	// Crear el servidor HTTP con el mock
-	server := NewHTTPServer(gin.New(), mockService, validate)
+	server := NewHTTPServer(gin.New(), nil, mockAuth, validate, auth.Middleware(nil, true))
 
 	// Definir el input y el output esperado
-	input := dto.CreateUser{
-		Name:     "Test User",
-		Email:    "test@example.com",
-		Nickname: "testuser",
-		Bio:      "Test bio",
-		Avatar:   "https://example.com/avatar.png",
+	input := dto.Register{
+		CreateUser: dto.CreateUser{
+			Name:     "Test User",
+			Email:    "test@example.com",
+			Nickname: "testuser",
+			Bio:      "Test bio",
+			Avatar:   "https://example.com/avatar.png",
+		},
+		Password: "contraseña-segura",
 	}
 
 	createdUser := dto.User{
@@ -47,8 +52,8 @@ func TestHTTPServer_CreateUser(t *testing.T) {
 		Following: 0,
 	}
 
-	// Configurar el mock para esperar la llamada a Create y devolver el usuario creado
-	mockService.On("Create", mock.Anything, &input).Return(&createdUser, nil)
+	// Configurar el mock para esperar la llamada a Register y devolver el usuario creado
+	mockAuth.On("Register", mock.Anything, &input).Return(&createdUser, nil)
 
 	// Convertir el input a JSON
 	body, err := json.Marshal(input)
@@ -77,14 +82,14 @@ func TestHTTPServer_CreateUser(t *testing.T) {
 	assert.Equal(t, createdUser, response)
 
 	// Verificar que el mock fue llamado
-	mockService.AssertExpectations(t)
+	mockAuth.AssertExpectations(t)
 }
 
 func TestHTTPServer_CreateUser_InvalidInput(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	mockService := new(mocks.UserService)
+	mockAuth := new(mocks.AuthService)
 	validate := validator.New()
-	server := NewHTTPServer(gin.New(), mockService, validate)
+	server := NewHTTPServer(gin.New(), nil, mockAuth, validate, auth.Middleware(nil, true))
 
 	// Input inválido (falta el nombre)
 	input := map[string]interface{}{
@@ -111,3 +116,132 @@ func TestHTTPServer_CreateUser_InvalidInput(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, response, "error")
 }
+
+func TestHTTPServer_CreateUser_RequiresPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockAuth := new(mocks.AuthService)
+	server := NewHTTPServer(gin.New(), nil, mockAuth, validator.New(), auth.Middleware(nil, true))
+
+	body, err := json.Marshal(dto.CreateUser{
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Nickname: "testuser",
+	})
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, "/users", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	server.engine.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	mockAuth.AssertNotCalled(t, "Register", mock.Anything, mock.Anything)
+}
+
+func TestHTTPServer_GetUser_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(mocks.UserService)
+	server := NewHTTPServer(gin.New(), mockService, nil, validator.New(), auth.Middleware(nil, true))
+
+	mockService.On("GetById", mock.Anything, "12345").Return(nil, models.ErrUserNotFound)
+
+	req, err := http.NewRequest(http.MethodGet, "/users/12345", nil)
+	assert.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	server.engine.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHTTPServer_UpdateMe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(mocks.UserService)
+	server := NewHTTPServer(gin.New(), mockService, nil, validator.New(), auth.Middleware(nil, true))
+
+	input := dto.UpdateUser{Bio: "Nueva bio"}
+	updatedUser := dto.User{ID: "12345", Name: "Test User", Bio: input.Bio}
+
+	// El usuario a actualizar es siempre el autenticado
+	mockService.On("Update", mock.Anything, "12345", &input).Return(&updatedUser, nil)
+
+	body, err := json.Marshal(input)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPatch, "/users/me", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-ID", "12345")
+
+	recorder := httptest.NewRecorder()
+	server.engine.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response dto.User
+	err = json.Unmarshal(recorder.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, updatedUser, response)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestHTTPServer_Block(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(mocks.UserService)
+	server := NewHTTPServer(gin.New(), mockService, nil, validator.New(), auth.Middleware(nil, true))
+
+	// El usuario autenticado bloquea al usuario de la ruta
+	mockService.On("Block", mock.Anything, "67890", "12345").Return(nil)
+	mockService.On("Block", mock.Anything, "12345", "12345").Return(models.ErrSelfAction)
+
+	req, err := http.NewRequest(http.MethodPost, "/users/67890/block", nil)
+	assert.NoError(t, err)
+	req.Header.Set("User-ID", "12345")
+
+	recorder := httptest.NewRecorder()
+	server.engine.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/users/12345/block", nil)
+	assert.NoError(t, err)
+	req.Header.Set("User-ID", "12345")
+
+	recorder = httptest.NewRecorder()
+	server.engine.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestHTTPServer_Suggestions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(mocks.UserService)
+	server := NewHTTPServer(gin.New(), mockService, nil, validator.New(), auth.Middleware(nil, true))
+
+	suggestions := []dto.Suggestion{{Follower: dto.Follower{ID: "67890", Nickname: "maria"}, Mutuals: 3}}
+	mockService.On("Suggestions", mock.Anything, "12345", &dto.SuggestionQuery{Size: 5}).Return(suggestions, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/users/me/suggestions?size=5", nil)
+	assert.NoError(t, err)
+	req.Header.Set("User-ID", "12345")
+
+	recorder := httptest.NewRecorder()
+	server.engine.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"users":[{"id":"67890","name":"","nickname":"maria","avatar":"","mutuals":3}]}`, recorder.Body.String())
+
+	// El tamaño máximo de la página es 50
+	req, err = http.NewRequest(http.MethodGet, "/users/me/suggestions?size=100", nil)
+	assert.NoError(t, err)
+	req.Header.Set("User-ID", "12345")
+
+	recorder = httptest.NewRecorder()
+	server.engine.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	mockService.AssertExpectations(t)
+}
@@ -1,9 +1,12 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"shared/auth"
 	"user_service/internal/application/dto"
+	"user_service/internal/domain/models"
 	"user_service/internal/interfaces"
 
 	"github.com/gin-gonic/gin"
@@ -14,13 +17,17 @@ type HTTPServer struct {
 	engine      *gin.Engine
 	validate    *validator.Validate
 	userService interfaces.UserService
+	authService interfaces.AuthService
+	auth        gin.HandlerFunc
 }
 
-func NewHTTPServer(engine *gin.Engine, userService interfaces.UserService, validate *validator.Validate) *HTTPServer {
+func NewHTTPServer(engine *gin.Engine, userService interfaces.UserService, authService interfaces.AuthService, validate *validator.Validate, auth gin.HandlerFunc) *HTTPServer {
 	server := &HTTPServer{
 		engine:      engine,
 		validate:    validate,
 		userService: userService,
+		authService: authService,
+		auth:        auth,
 	}
 	server.registerRoutes()
 	return server
@@ -33,48 +40,101 @@ func (s *HTTPServer) Run(port string) {
 }
 
 func (s *HTTPServer) registerRoutes() {
-	s.engine.POST("/users", s.create)
-	authorized := s.engine.Group("/", AuthMiddleware())
+	s.engine.POST("/auth/register", s.register)
+	s.engine.POST("/auth/login", s.login)
+	s.engine.POST("/auth/refresh", s.refresh)
+	// POST /users se mantiene por compatibilidad y también exige contraseña
+	s.engine.POST("/users", s.register)
+	s.engine.GET("/users/search", s.search)
+	s.engine.GET("/users/:id", s.get)
+	s.engine.GET("/users/by-nickname/:nickname", s.getByNickname)
+	s.engine.GET("/users/:id/followers", s.followers)
+	s.engine.GET("/users/:id/following", s.following)
+	s.engine.GET("/users/:id/relationship/:other", s.relationship)
+	authorized := s.engine.Group("/", s.auth)
 	{
+		authorized.GET("/sessions", s.sessions)
+		authorized.DELETE("/sessions", s.revokeSessions)
+		authorized.DELETE("/sessions/:id", s.revokeSession)
+		authorized.PATCH("/users/me", s.update)
+		authorized.DELETE("/users/me", s.delete)
+		authorized.GET("/users/me/suggestions", s.suggestions)
 		authorized.POST("/users/:id/follow", s.follow)
 		authorized.POST("/users/:id/unfollow", s.unfollow)
+		authorized.GET("/follow-requests", s.followRequests)
+		authorized.POST("/follow-requests/:id/approve", s.approveFollowRequest)
+		authorized.POST("/follow-requests/:id/reject", s.rejectFollowRequest)
+		authorized.POST("/users/:id/block", s.block)
+		authorized.DELETE("/users/:id/block", s.unblock)
+		authorized.POST("/users/:id/mute", s.mute)
+		authorized.DELETE("/users/:id/mute", s.unmute)
 	}
 }
 
-func (s *HTTPServer) create(c *gin.Context) {
-	var user dto.CreateUser
+func (s *HTTPServer) get(c *gin.Context) {
+	user, err := s.userService.GetById(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (s *HTTPServer) getByNickname(c *gin.Context) {
+	user, err := s.userService.GetByNickname(c.Request.Context(), c.Param("nickname"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (s *HTTPServer) update(c *gin.Context) {
+	var user dto.UpdateUser
 
-	// Intentar vincular el cuerpo JSON al DTO
 	if err := c.ShouldBindJSON(&user); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Validar los datos con el validador
 	if err := s.validate.Struct(user); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
 		return
 	}
 
-	// Crear el usuario usando el servicio
-	createdUser, err := s.userService.Create(c.Request.Context(), &user)
+	updatedUser, err := s.userService.Update(c.Request.Context(), c.GetString(auth.UserIDKey), &user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedUser)
+}
+
+func (s *HTTPServer) delete(c *gin.Context) {
+	if err := s.userService.Delete(c.Request.Context(), c.GetString(auth.UserIDKey)); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	// Responder con el usuario creado
-	c.JSON(http.StatusCreated, createdUser)
+	c.JSON(http.StatusOK, gin.H{"message": "Usuario eliminado correctamente."})
 }
 
 func (s *HTTPServer) follow(c *gin.Context) {
-	userID, _ := c.Get("userID")
-	id := userID.(string)
-	followerID := c.Param("id")
+	// El usuario autenticado pasa a seguir al usuario de la ruta
+	followerID := c.GetString(auth.UserIDKey)
+	id := c.Param("id")
 
-	err := s.userService.Follow(c.Request.Context(), id, followerID)
+	pending, err := s.userService.Follow(c.Request.Context(), id, followerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if pending {
+		c.JSON(http.StatusAccepted, gin.H{"message": "Solicitud de seguimiento enviada."})
 		return
 	}
 
@@ -82,9 +142,9 @@ func (s *HTTPServer) follow(c *gin.Context) {
 }
 
 func (s *HTTPServer) unfollow(c *gin.Context) {
-	userID, _ := c.Get("userID")
-	id := userID.(string)
-	followerID := c.Param("id")
+	// El usuario autenticado deja de seguir al usuario de la ruta
+	followerID := c.GetString(auth.UserIDKey)
+	id := c.Param("id")
 
 	err := s.userService.Unfollow(c.Request.Context(), id, followerID)
 	if err != nil {
@@ -94,3 +154,198 @@ func (s *HTTPServer) unfollow(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Usuario dejado de seguir correctamente."})
 }
+
+func (s *HTTPServer) search(c *gin.Context) {
+	var query dto.UserSearchQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	users, err := s.userService.Search(c.Request.Context(), &query)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": users})
+}
+
+func (s *HTTPServer) followers(c *gin.Context) {
+	var query dto.FollowQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	page, err := s.userService.Followers(c.Request.Context(), c.Param("id"), &query)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (s *HTTPServer) following(c *gin.Context) {
+	var query dto.FollowQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	page, err := s.userService.Following(c.Request.Context(), c.Param("id"), &query)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (s *HTTPServer) suggestions(c *gin.Context) {
+	var query dto.SuggestionQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	suggestions, err := s.userService.Suggestions(c.Request.Context(), c.GetString(auth.UserIDKey), &query)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": suggestions})
+}
+
+func (s *HTTPServer) relationship(c *gin.Context) {
+	relationship, err := s.userService.Relationship(c.Request.Context(), c.Param("id"), c.Param("other"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, relationship)
+}
+
+func (s *HTTPServer) followRequests(c *gin.Context) {
+	var query dto.FollowQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.validate.Struct(query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error de validación: %s", err.Error())})
+		return
+	}
+
+	page, err := s.userService.FollowRequests(c.Request.Context(), c.GetString(auth.UserIDKey), &query)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// approveFollowRequest acepta la solicitud del usuario de la ruta para seguir
+// al usuario autenticado.
+func (s *HTTPServer) approveFollowRequest(c *gin.Context) {
+	if err := s.userService.ApproveFollowRequest(c.Request.Context(), c.GetString(auth.UserIDKey), c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Solicitud de seguimiento aprobada."})
+}
+
+func (s *HTTPServer) rejectFollowRequest(c *gin.Context) {
+	if err := s.userService.RejectFollowRequest(c.Request.Context(), c.GetString(auth.UserIDKey), c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Solicitud de seguimiento rechazada."})
+}
+
+func (s *HTTPServer) block(c *gin.Context) {
+	if err := s.userService.Block(c.Request.Context(), c.Param("id"), c.GetString(auth.UserIDKey)); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Usuario bloqueado correctamente."})
+}
+
+func (s *HTTPServer) unblock(c *gin.Context) {
+	if err := s.userService.Unblock(c.Request.Context(), c.Param("id"), c.GetString(auth.UserIDKey)); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Usuario desbloqueado correctamente."})
+}
+
+func (s *HTTPServer) mute(c *gin.Context) {
+	if err := s.userService.Mute(c.Request.Context(), c.Param("id"), c.GetString(auth.UserIDKey)); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Usuario silenciado correctamente."})
+}
+
+func (s *HTTPServer) unmute(c *gin.Context) {
+	if err := s.userService.Unmute(c.Request.Context(), c.Param("id"), c.GetString(auth.UserIDKey)); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Usuario dejado de silenciar correctamente."})
+}
+
+// errorStatus traduce los errores de dominio al código HTTP correspondiente.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrUserNotFound), errors.Is(err, models.ErrSessionNotFound),
+		errors.Is(err, models.ErrFollowRequestNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrNicknameTaken), errors.Is(err, models.ErrAlreadyFollowing):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrInvalidCursor), errors.Is(err, models.ErrSelfAction):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrBlocked):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrInvalidCredentials), errors.Is(err, auth.ErrInvalidToken):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"user_service/internal/application/dto"
+	"user_service/internal/domain/models"
+
+	"gorm.io/gorm"
+)
+
+const defaultPageSize = 20
+
+type followRow struct {
+	models.User `gorm:"embedded"`
+	FollowID    string
+	FollowedAt  time.Time
+}
+
+func (r *repository) Followers(ctx context.Context, id string, query *dto.FollowQuery) ([]*models.User, string, error) {
+	return r.follows(ctx, "followers", "follower_id", "user_id", id, query)
+}
+
+func (r *repository) Following(ctx context.Context, id string, query *dto.FollowQuery) ([]*models.User, string, error) {
+	return r.follows(ctx, "followers", "user_id", "follower_id", id, query)
+}
+
+// FollowRequests lista los usuarios con una solicitud de seguimiento pendiente
+// hacia id.
+func (r *repository) FollowRequests(ctx context.Context, id string, query *dto.FollowQuery) ([]*models.User, string, error) {
+	return r.follows(ctx, "follow_requests", "follower_id", "user_id", id, query)
+}
+
+// ApproveFollowRequest acepta la solicitud de followerID para seguir a userID.
+func (r *repository) ApproveFollowRequest(ctx context.Context, userID, followerID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.deleteFollowRequest(tx, userID, followerID); err != nil {
+			return err
+		}
+
+		return r.createFollow(ctx, tx, userID, followerID)
+	})
+}
+
+// RejectFollowRequest descarta la solicitud de followerID para seguir a userID.
+func (r *repository) RejectFollowRequest(ctx context.Context, userID, followerID string) error {
+	return r.deleteFollowRequest(r.db.WithContext(ctx), userID, followerID)
+}
+
+func (r *repository) deleteFollowRequest(tx *gorm.DB, userID, followerID string) error {
+	result := tx.Where("user_id = ? AND follower_id = ?", userID, followerID).Delete(&models.FollowRequest{})
+	if result.Error != nil {
+		return fmt.Errorf("error al eliminar la solicitud de seguimiento: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrFollowRequestNotFound
+	}
+
+	return nil
+}
+
+// follows lista los usuarios relacionados con id a través de la tabla de
+// seguimientos o de solicitudes, de la relación más reciente a la más antigua.
+func (r *repository) follows(ctx context.Context, table, joinColumn, filterColumn, id string, query *dto.FollowQuery) ([]*models.User, string, error) {
+	if _, err := r.GetById(ctx, id); err != nil {
+		return nil, "", err
+	}
+
+	size := query.Size
+	if size <= 0 {
+		size = defaultPageSize
+	}
+
+	db := r.db.WithContext(ctx).
+		Table(table).
+		Select(fmt.Sprintf("users.*, %[1]s.id AS follow_id, %[1]s.created_at AS followed_at", table)).
+		Joins(fmt.Sprintf("JOIN users ON users.id = %s.%s", table, joinColumn)).
+		Where(fmt.Sprintf("%s.%s = ?", table, filterColumn), id)
+
+	if query.Cursor != "" {
+		createdAt, followID, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		db = db.Where(fmt.Sprintf("%[1]s.created_at < ? OR (%[1]s.created_at = ? AND %[1]s.id < ?)", table), createdAt, createdAt, followID)
+	}
+
+	// Se pide un elemento extra para saber si existe una página siguiente
+	var rows []*followRow
+	if err := db.Order(fmt.Sprintf("%[1]s.created_at DESC, %[1]s.id DESC", table)).Limit(size + 1).Scan(&rows).Error; err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, "", fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+		}
+		return nil, "", fmt.Errorf("error al obtener las relaciones de seguimiento: %w", err)
+	}
+
+	nextCursor := ""
+	if len(rows) > size {
+		rows = rows[:size]
+		last := rows[size-1]
+		nextCursor = encodeCursor(last.FollowedAt, last.FollowID)
+	}
+
+	users := make([]*models.User, len(rows))
+	for i, row := range rows {
+		user := row.User
+		users[i] = &user
+	}
+
+	return users, nextCursor, nil
+}
+
+func (r *repository) Relationship(ctx context.Context, id, otherID string) (bool, bool, error) {
+	pipe := r.redis.Pipeline()
+	following := pipe.SIsMember(ctx, fmt.Sprintf("following:%s", id), otherID)
+	followedBy := pipe.SIsMember(ctx, fmt.Sprintf("followers:%s", id), otherID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, false, fmt.Errorf("error al consultar la relación en Redis: %w", err)
+	}
+
+	return following.Val(), followedBy.Val(), nil
+}
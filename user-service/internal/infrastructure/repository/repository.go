@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"user_service/internal/application/dto"
 	"user_service/internal/domain/models"
 	"user_service/internal/interfaces"
@@ -13,6 +14,24 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	// followStream es el stream donde se publican los cambios de seguimiento
+	// para que el timeline-service complete o purgue los timelines
+	followStream  = "follow_stream"
+	followEvent   = "follow"
+	unfollowEvent = "unfollow"
+	// followStreamMaxLen limita aproximadamente las entradas que conserva el
+	// stream; los consumidores solo necesitan las aún no procesadas
+	followStreamMaxLen = 100000
+
+	// privateUsersKey es el conjunto de cuentas privadas que consulta el
+	// tweets-service para restringir la lectura de sus tweets
+	privateUsersKey = "private_users"
+	// nicknamesKey es el hash nickname -> id con el que el tweets-service
+	// resuelve las menciones
+	nicknamesKey = "nicknames"
+)
+
 type repository struct {
 	db    *gorm.DB
 	redis *redis.Client
@@ -29,15 +48,274 @@ func (r *repository) Create(ctx context.Context, createUser *dto.CreateUser) (*m
 		Email:    createUser.Email,
 		Bio:      createUser.Bio,
 		Avatar:   createUser.Avatar,
+
+		PasswordHash: createUser.PasswordHash,
+	}
+	userModel.SearchName = searchName(userModel)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		taken, err := nicknameTaken(tx, userModel.Nickname, "")
+		if err != nil {
+			return err
+		}
+		if taken {
+			return models.ErrNicknameTaken
+		}
+
+		if err := tx.Create(userModel).Error; err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+			}
+			return fmt.Errorf("error al crear el usuario: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Almacenar en Redis
+	r.cacheUser(ctx, userModel)
+
+	return userModel, nil
+}
+
+func (r *repository) GetById(ctx context.Context, id string) (*models.User, error) {
+	user := &models.User{}
+	if err := r.db.WithContext(ctx).First(user, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrUserNotFound
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+		}
+		return nil, fmt.Errorf("error al obtener el usuario: %w", err)
 	}
 
-	if err := r.db.WithContext(ctx).Create(userModel).Error; err != nil {
+	return user, nil
+}
+
+func (r *repository) GetByNickname(ctx context.Context, nickname string) (*models.User, error) {
+	// Los nicknames pueden estar almacenados con o sin el prefijo @ y no
+	// distinguen mayúsculas
+	nickname = nicknameKey(nickname)
+
+	user := &models.User{}
+	if err := r.db.WithContext(ctx).
+		Where("LOWER(nickname) IN ?", []string{nickname, "@" + nickname}).
+		First(user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrUserNotFound
+		}
 		if ctx.Err() == context.DeadlineExceeded {
 			return nil, fmt.Errorf("operación cancelada por exceder el límite de tiempo")
 		}
-		return nil, fmt.Errorf("error al crear el usuario: %w", err)
+		return nil, fmt.Errorf("error al obtener el usuario: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *repository) Update(ctx context.Context, id string, updateUser *dto.UpdateUser) (*models.User, error) {
+	user := &models.User{}
+	var previous models.User
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(user, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrUserNotFound
+			}
+			return fmt.Errorf("error al obtener el usuario: %w", err)
+		}
+		previous = *user
+
+		if updateUser.Nickname != "" && updateUser.Nickname != user.Nickname {
+			taken, err := nicknameTaken(tx, updateUser.Nickname, id)
+			if err != nil {
+				return err
+			}
+			if taken {
+				return models.ErrNicknameTaken
+			}
+		}
+
+		// Solo se actualizan los campos enviados
+		if err := tx.Model(user).Updates(models.User{
+			Name:     updateUser.Name,
+			Nickname: updateUser.Nickname,
+			Bio:      updateUser.Bio,
+			Avatar:   updateUser.Avatar,
+		}).Error; err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+			}
+			return fmt.Errorf("error al actualizar el usuario: %w", err)
+		}
+
+		// Updates ignora los valores cero, por lo que Private se actualiza aparte
+		if updateUser.Private != nil {
+			if err := tx.Model(user).Update("private", *updateUser.Private).Error; err != nil {
+				return fmt.Errorf("error al actualizar el usuario: %w", err)
+			}
+		}
+
+		if name := searchName(user); name != user.SearchName {
+			if err := tx.Model(user).UpdateColumn("search_name", name).Error; err != nil {
+				return fmt.Errorf("error al actualizar el usuario: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// El nickname anterior deja de resolver menciones y los términos
+	// anteriores dejan de encontrar al usuario; cacheUser agrega los nuevos
+	pipe := r.redis.TxPipeline()
+	if nicknameKey(previous.Nickname) != nicknameKey(user.Nickname) {
+		pipe.HDel(ctx, nicknamesKey, nicknameKey(previous.Nickname))
 	}
+	if searchName(&previous) != searchName(user) {
+		unindexUser(ctx, pipe, &previous)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("error al actualizar el nickname en Redis: %w", err)
+	}
+
+	// Mantener sincronizada la copia que lee el timeline-service
+	if err := r.cacheUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user := &models.User{}
+		if err := tx.First(user, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrUserNotFound
+			}
+			return fmt.Errorf("error al obtener el usuario: %w", err)
+		}
+
+		// Deshacer las relaciones de seguimiento para mantener los contadores
+		var followers, following []string
+		if err := tx.Model(&models.Follower{}).Where("user_id = ?", id).Pluck("follower_id", &followers).Error; err != nil {
+			return fmt.Errorf("error al obtener los seguidores: %w", err)
+		}
+		if err := tx.Model(&models.Follower{}).Where("follower_id = ?", id).Pluck("user_id", &following).Error; err != nil {
+			return fmt.Errorf("error al obtener los seguidos: %w", err)
+		}
+
+		if len(followers) > 0 {
+			if err := tx.Model(&models.User{}).Where("id IN ?", followers).UpdateColumn("following", gorm.Expr("following - ?", 1)).Error; err != nil {
+				return fmt.Errorf("error al decrementar los seguidos: %w", err)
+			}
+		}
+		if len(following) > 0 {
+			if err := tx.Model(&models.User{}).Where("id IN ?", following).UpdateColumn("followers", gorm.Expr("followers - ?", 1)).Error; err != nil {
+				return fmt.Errorf("error al decrementar los seguidores: %w", err)
+			}
+		}
 
+		if err := tx.Where("user_id = ? OR follower_id = ?", id, id).Delete(&models.Follower{}).Error; err != nil {
+			return fmt.Errorf("error al eliminar los registros de seguimiento: %w", err)
+		}
+
+		// Eliminar los bloqueos y silencios en los que participa el usuario
+		var blocking, blockedBy, mutedBy []string
+		if err := tx.Model(&models.Block{}).Where("user_id = ?", id).Pluck("blocked_id", &blocking).Error; err != nil {
+			return fmt.Errorf("error al obtener los bloqueos: %w", err)
+		}
+		if err := tx.Model(&models.Block{}).Where("blocked_id = ?", id).Pluck("user_id", &blockedBy).Error; err != nil {
+			return fmt.Errorf("error al obtener los bloqueos: %w", err)
+		}
+		if err := tx.Model(&models.Mute{}).Where("muted_id = ?", id).Pluck("user_id", &mutedBy).Error; err != nil {
+			return fmt.Errorf("error al obtener los silencios: %w", err)
+		}
+		if err := tx.Where("user_id = ? OR blocked_id = ?", id, id).Delete(&models.Block{}).Error; err != nil {
+			return fmt.Errorf("error al eliminar los bloqueos: %w", err)
+		}
+		if err := tx.Where("user_id = ? OR muted_id = ?", id, id).Delete(&models.Mute{}).Error; err != nil {
+			return fmt.Errorf("error al eliminar los silencios: %w", err)
+		}
+		if err := tx.Where("user_id = ? OR follower_id = ?", id, id).Delete(&models.FollowRequest{}).Error; err != nil {
+			return fmt.Errorf("error al eliminar las solicitudes de seguimiento: %w", err)
+		}
+
+		if err := tx.Delete(user).Error; err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+			}
+			return fmt.Errorf("error al eliminar el usuario: %w", err)
+		}
+
+		// Actualizar Redis dentro de la transacción
+		pipe := r.redis.TxPipeline()
+		for _, followerID := range followers {
+			pipe.SRem(ctx, fmt.Sprintf("following:%s", followerID), id)
+			publishFollowEvent(ctx, pipe, unfollowEvent, id, followerID)
+		}
+		for _, userID := range following {
+			pipe.SRem(ctx, fmt.Sprintf("followers:%s", userID), id)
+		}
+		for _, userID := range blocking {
+			pipe.SRem(ctx, fmt.Sprintf("blocked_by:%s", userID), id)
+		}
+		for _, userID := range blockedBy {
+			pipe.SRem(ctx, fmt.Sprintf("blocking:%s", userID), id)
+		}
+		for _, userID := range mutedBy {
+			pipe.SRem(ctx, fmt.Sprintf("muting:%s", userID), id)
+		}
+		pipe.SRem(ctx, privateUsersKey, id)
+		pipe.HDel(ctx, nicknamesKey, nicknameKey(user.Nickname))
+		unindexUser(ctx, pipe, user)
+		pipe.Del(ctx,
+			fmt.Sprintf("users:%s", id),
+			fmt.Sprintf("followers:%s", id),
+			fmt.Sprintf("following:%s", id),
+			fmt.Sprintf("blocking:%s", id),
+			fmt.Sprintf("blocked_by:%s", id),
+			fmt.Sprintf("muting:%s", id),
+			suggestionsKey(id),
+		)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("error al actualizar Redis: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Las sesiones de un usuario eliminado dejan de ser válidas en todos los servicios
+	return r.RevokeSessions(ctx, id)
+}
+
+func publishFollowEvent(ctx context.Context, pipe redis.Pipeliner, event, userID, followerID string) {
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: followStream,
+		MaxLen: followStreamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"type":        event,
+			"user_id":     userID,
+			"follower_id": followerID,
+		},
+	})
+}
+
+// cacheUser guarda en users:<id> los datos del usuario que necesita el
+// timeline-service para hidratar los tweets y refleja si la cuenta es privada.
+func (r *repository) cacheUser(ctx context.Context, user *models.User) error {
 	// Serializar el usuario para Redis
 	userData, err := json.Marshal(struct {
 		ID       string `json:"id"`
@@ -45,27 +323,90 @@ func (r *repository) Create(ctx context.Context, createUser *dto.CreateUser) (*m
 		Nickname string `json:"nickname"`
 		Avatar   string `json:"avatar"`
 	}{
-		ID:       userModel.ID,
-		Name:     userModel.Name,
-		Nickname: userModel.Nickname,
-		Avatar:   userModel.Avatar,
+		ID:       user.ID,
+		Name:     user.Name,
+		Nickname: user.Nickname,
+		Avatar:   user.Avatar,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("error al serializar el usuario: %w", err)
+		return fmt.Errorf("error al serializar el usuario: %w", err)
 	}
 
-	// Almacenar en Redis
-	key := fmt.Sprintf("users:%s", userModel.ID)
-	r.redis.Set(ctx, key, userData, 0).Err()
+	pipe := r.redis.TxPipeline()
+	pipe.Set(ctx, fmt.Sprintf("users:%s", user.ID), userData, 0)
+	pipe.HSet(ctx, nicknamesKey, nicknameKey(user.Nickname), user.ID)
+	indexUser(ctx, pipe, user)
+	if user.Private {
+		pipe.SAdd(ctx, privateUsersKey, user.ID)
+	} else {
+		pipe.SRem(ctx, privateUsersKey, user.ID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("error al guardar el usuario en Redis: %w", err)
+	}
 
-	return userModel, nil
+	return nil
 }
 
-func (r *repository) Follow(ctx context.Context, userID, followerID string) error {
+// SyncCache vuelve a escribir en Redis la copia de todos los usuarios, para
+// completar los índices agregados después de crearlos.
+func (r *repository) SyncCache(ctx context.Context) error {
+	var users []*models.User
+	result := r.db.WithContext(ctx).FindInBatches(&users, 500, func(tx *gorm.DB, batch int) error {
+		for _, user := range users {
+			// Los usuarios creados antes de la búsqueda no tienen SearchName
+			if user.SearchName == "" {
+				user.SearchName = searchName(user)
+				if err := r.db.WithContext(ctx).Model(user).UpdateColumn("search_name", user.SearchName).Error; err != nil {
+					return fmt.Errorf("error al actualizar el usuario: %w", err)
+				}
+			}
+			if err := r.cacheUser(ctx, user); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if result.Error != nil {
+		return fmt.Errorf("error al sincronizar los usuarios en Redis: %w", result.Error)
+	}
+
+	return nil
+}
+
+// nicknameKey normaliza un nickname para buscarlo sin distinguir mayúsculas
+// ni el prefijo @.
+func nicknameKey(nickname string) string {
+	return strings.ToLower(strings.TrimPrefix(nickname, "@"))
+}
+
+// nicknameTaken indica si otro usuario distinto de excludeID ya usa el
+// nickname. Igual que en el hash nicknames, "Nick", "nick" y "@nick" son el
+// mismo nickname.
+func nicknameTaken(tx *gorm.DB, nickname, excludeID string) (bool, error) {
+	key := nicknameKey(nickname)
+
+	db := tx.Model(&models.User{}).Where("LOWER(nickname) IN ?", []string{key, "@" + key})
+	if excludeID != "" {
+		db = db.Where("id <> ?", excludeID)
+	}
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("error al verificar el nickname: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// Follow hace que followerID siga a userID. Si la cuenta de userID es privada
+// solo se registra una solicitud pendiente y se devuelve pending en true.
+func (r *repository) Follow(ctx context.Context, userID, followerID string) (bool, error) {
 	if userID == followerID {
-		return fmt.Errorf("un usuario no puede seguirse a sí mismo")
+		return false, fmt.Errorf("un usuario no puede seguirse a sí mismo")
 	}
 
+	pending := false
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var user, follower models.User
 
@@ -84,6 +425,15 @@ func (r *repository) Follow(ctx context.Context, userID, followerID string) erro
 			return fmt.Errorf("error al obtener el usuario seguidor: %w", err)
 		}
 
+		// No se puede seguir a un usuario con el que existe un bloqueo
+		blocked, err := r.isBlocked(tx, userID, followerID)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return models.ErrBlocked
+		}
+
 		// Verificar si ya sigue al usuario
 		var count int64
 		if err := tx.Model(&models.Follower{}).
@@ -92,39 +442,55 @@ func (r *repository) Follow(ctx context.Context, userID, followerID string) erro
 			return fmt.Errorf("error al verificar seguimiento: %w", err)
 		}
 		if count > 0 {
-			return fmt.Errorf("el usuario ya sigue a este usuario")
+			return models.ErrAlreadyFollowing
 		}
 
-		// Crear la relación de seguimiento
-		followerRecord := &models.Follower{
-			UserID:     userID,
-			FollowerID: followerID,
-		}
-		if err := tx.Create(followerRecord).Error; err != nil {
-			return fmt.Errorf("error al crear el registro de seguimiento: %w", err)
+		// Las cuentas privadas deben aprobar la solicitud; repetirla no tiene efecto
+		if user.Private {
+			request := &models.FollowRequest{}
+			if err := tx.Where(models.FollowRequest{UserID: userID, FollowerID: followerID}).FirstOrCreate(request).Error; err != nil {
+				return fmt.Errorf("error al crear la solicitud de seguimiento: %w", err)
+			}
+			pending = true
+			return nil
 		}
 
-		// Actualizar contadores de seguidores y seguidos
-		if err := tx.Model(&user).UpdateColumn("followers", gorm.Expr("followers + ?", 1)).Error; err != nil {
-			return fmt.Errorf("error al incrementar los seguidores: %w", err)
-		}
+		return r.createFollow(ctx, tx, userID, followerID)
+	})
 
-		if err := tx.Model(&follower).UpdateColumn("following", gorm.Expr("following + ?", 1)).Error; err != nil {
-			return fmt.Errorf("error al incrementar los seguidos: %w", err)
-		}
+	return pending, err
+}
 
-		// Actualizar Redis dentro de la transacción
-		pipe := r.redis.TxPipeline()
-		pipe.SAdd(ctx, fmt.Sprintf("following:%s", followerID), userID)
-		pipe.SAdd(ctx, fmt.Sprintf("followers:%s", userID), followerID)
-		if _, err := pipe.Exec(ctx); err != nil {
-			return fmt.Errorf("error al actualizar Redis: %w", err)
-		}
+// createFollow crea el seguimiento de followerID a userID, actualiza los
+// contadores y publica el cambio en Redis.
+func (r *repository) createFollow(ctx context.Context, tx *gorm.DB, userID, followerID string) error {
+	followerRecord := &models.Follower{
+		UserID:     userID,
+		FollowerID: followerID,
+	}
+	if err := tx.Create(followerRecord).Error; err != nil {
+		return fmt.Errorf("error al crear el registro de seguimiento: %w", err)
+	}
 
-		return nil
-	})
+	// Actualizar contadores de seguidores y seguidos
+	if err := tx.Model(&models.User{}).Where("id = ?", userID).UpdateColumn("followers", gorm.Expr("followers + ?", 1)).Error; err != nil {
+		return fmt.Errorf("error al incrementar los seguidores: %w", err)
+	}
 
-	return err
+	if err := tx.Model(&models.User{}).Where("id = ?", followerID).UpdateColumn("following", gorm.Expr("following + ?", 1)).Error; err != nil {
+		return fmt.Errorf("error al incrementar los seguidos: %w", err)
+	}
+
+	// Actualizar Redis dentro de la transacción
+	pipe := r.redis.TxPipeline()
+	pipe.SAdd(ctx, fmt.Sprintf("following:%s", followerID), userID)
+	pipe.SAdd(ctx, fmt.Sprintf("followers:%s", userID), followerID)
+	publishFollowEvent(ctx, pipe, followEvent, userID, followerID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("error al actualizar Redis: %w", err)
+	}
+
+	return nil
 }
 
 func (r *repository) Unfollow(ctx context.Context, userID, followerID string) error {
@@ -137,6 +503,14 @@ func (r *repository) Unfollow(ctx context.Context, userID, followerID string) er
 		var followerRecord models.Follower
 		if err := tx.Where("user_id = ? AND follower_id = ?", userID, followerID).First(&followerRecord).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// Dejar de seguir una cuenta privada aún no aprobada cancela la solicitud
+				result := tx.Where("user_id = ? AND follower_id = ?", userID, followerID).Delete(&models.FollowRequest{})
+				if result.Error != nil {
+					return fmt.Errorf("error al cancelar la solicitud de seguimiento: %w", result.Error)
+				}
+				if result.RowsAffected > 0 {
+					return nil
+				}
 				return fmt.Errorf("el usuario no sigue a este usuario")
 			}
 			return fmt.Errorf("error al verificar seguimiento: %w", err)
@@ -160,6 +534,7 @@ func (r *repository) Unfollow(ctx context.Context, userID, followerID string) er
 		pipe := r.redis.TxPipeline()
 		pipe.SRem(ctx, fmt.Sprintf("following:%s", followerID), userID)
 		pipe.SRem(ctx, fmt.Sprintf("followers:%s", userID), followerID)
+		publishFollowEvent(ctx, pipe, unfollowEvent, userID, followerID)
 		if _, err := pipe.Exec(ctx); err != nil {
 			return fmt.Errorf("error al actualizar Redis: %w", err)
 		}
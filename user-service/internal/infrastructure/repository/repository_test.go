@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 	"user_service/internal/application/dto"
 	"user_service/internal/domain/models"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/glebarez/sqlite"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
@@ -54,3 +56,316 @@ func TestRepository_Create(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, user.ID, dbUser.ID)
 }
+
+func TestRepository_GetByNickname(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to in-memory database: %v", err)
+	}
+
+	err = db.AutoMigrate(&models.User{}, &models.Follower{})
+	if err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	repo := NewRepository(db, redis.NewClient(&redis.Options{}))
+
+	// Los usuarios del seeder guardan el nickname con el prefijo @
+	user := &models.User{Name: "María Gómez", Email: "maria@example.com", Nickname: "@mary"}
+	assert.NoError(t, db.Create(user).Error)
+
+	found, err := repo.GetByNickname(context.Background(), "mary")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, found.ID)
+
+	found, err = repo.GetByNickname(context.Background(), "@mary")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, found.ID)
+
+	_, err = repo.GetByNickname(context.Background(), "nadie")
+	assert.ErrorIs(t, err, models.ErrUserNotFound)
+}
+
+func TestRepository_Update_NicknameTaken(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to in-memory database: %v", err)
+	}
+
+	err = db.AutoMigrate(&models.User{}, &models.Follower{})
+	if err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	repo := NewRepository(db, redis.NewClient(&redis.Options{}))
+
+	// El nickname existente tiene el prefijo @ y el nuevo no
+	taken := &models.User{Name: "María Gómez", Email: "maria@example.com", Nickname: "@mary"}
+	user := &models.User{Name: "Otro", Email: "otro@example.com", Nickname: "otro"}
+	assert.NoError(t, db.Create(taken).Error)
+	assert.NoError(t, db.Create(user).Error)
+
+	_, err = repo.Update(context.Background(), user.ID, &dto.UpdateUser{Nickname: "mary"})
+	assert.ErrorIs(t, err, models.ErrNicknameTaken)
+
+	// Tampoco se distinguen las mayúsculas
+	_, err = repo.Update(context.Background(), user.ID, &dto.UpdateUser{Nickname: "Mary"})
+	assert.ErrorIs(t, err, models.ErrNicknameTaken)
+
+	var stored models.User
+	assert.NoError(t, db.First(&stored, "id = ?", user.ID).Error)
+	assert.Equal(t, "otro", stored.Nickname)
+}
+
+func TestRepository_Create_NicknameTaken(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to in-memory database: %v", err)
+	}
+
+	err = db.AutoMigrate(&models.User{})
+	if err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	server := miniredis.RunT(t)
+	repo := NewRepository(db, redis.NewClient(&redis.Options{Addr: server.Addr()}))
+
+	_, err = repo.Create(context.Background(), &dto.CreateUser{Name: "María Gómez", Email: "maria@example.com", Nickname: "@Mary"})
+	assert.NoError(t, err)
+
+	for _, nickname := range []string{"mary", "MARY", "@mary"} {
+		_, err = repo.Create(context.Background(), &dto.CreateUser{Name: "Otra", Email: "otra@example.com", Nickname: nickname})
+		assert.ErrorIs(t, err, models.ErrNicknameTaken, nickname)
+	}
+
+	// La búsqueda por nickname tampoco distingue mayúsculas
+	user, err := repo.GetByNickname(context.Background(), "mARY")
+	assert.NoError(t, err)
+	assert.Equal(t, "@Mary", user.Nickname)
+}
+
+func TestRepository_Followers(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to in-memory database: %v", err)
+	}
+
+	err = db.AutoMigrate(&models.User{}, &models.Follower{})
+	if err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	repo := NewRepository(db, redis.NewClient(&redis.Options{}))
+
+	user := &models.User{Name: "Juan Pérez", Email: "juan@example.com", Nickname: "@juanito"}
+	assert.NoError(t, db.Create(user).Error)
+
+	base := time.Now()
+	for i, nickname := range []string{"@mary", "@carlitos", "@anita"} {
+		follower := &models.User{Name: nickname, Email: nickname + "@example.com", Nickname: nickname}
+		assert.NoError(t, db.Create(follower).Error)
+		assert.NoError(t, db.Create(&models.Follower{
+			UserID:     user.ID,
+			FollowerID: follower.ID,
+			CreatedAt:  base.Add(time.Duration(i) * time.Second),
+		}).Error)
+	}
+
+	// Los seguidores más recientes aparecen primero
+	first, next, err := repo.Followers(context.Background(), user.ID, &dto.FollowQuery{Size: 2})
+	assert.NoError(t, err)
+	assert.Len(t, first, 2)
+	assert.Equal(t, "@anita", first[0].Nickname)
+	assert.NotEmpty(t, next)
+
+	second, next, err := repo.Followers(context.Background(), user.ID, &dto.FollowQuery{Size: 2, Cursor: next})
+	assert.NoError(t, err)
+	assert.Len(t, second, 1)
+	assert.Equal(t, "@mary", second[0].Nickname)
+	assert.Empty(t, next)
+
+	following, _, err := repo.Following(context.Background(), second[0].ID, &dto.FollowQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, following, 1)
+	assert.Equal(t, user.ID, following[0].ID)
+}
+
+func TestRepository_Follow_PrivateAccount(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to in-memory database: %v", err)
+	}
+
+	err = db.AutoMigrate(&models.User{}, &models.Follower{}, &models.Block{}, &models.FollowRequest{})
+	if err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	repo := NewRepository(db, redis.NewClient(&redis.Options{}))
+
+	user := &models.User{Name: "Private", Email: "private@example.com", Nickname: "private", Private: true}
+	follower := &models.User{Name: "Follower", Email: "follower@example.com", Nickname: "follower"}
+	assert.NoError(t, db.Create(user).Error)
+	assert.NoError(t, db.Create(follower).Error)
+
+	// Seguir una cuenta privada solo deja una solicitud pendiente
+	pending, err := repo.Follow(context.Background(), user.ID, follower.ID)
+	assert.NoError(t, err)
+	assert.True(t, pending)
+
+	var follows int64
+	assert.NoError(t, db.Model(&models.Follower{}).Count(&follows).Error)
+	assert.Equal(t, int64(0), follows)
+
+	requests, _, err := repo.FollowRequests(context.Background(), user.ID, &dto.FollowQuery{})
+	assert.NoError(t, err)
+	if assert.Len(t, requests, 1) {
+		assert.Equal(t, follower.ID, requests[0].ID)
+	}
+
+	assert.NoError(t, repo.RejectFollowRequest(context.Background(), user.ID, follower.ID))
+	assert.ErrorIs(t, repo.RejectFollowRequest(context.Background(), user.ID, follower.ID), models.ErrFollowRequestNotFound)
+}
+
+func TestRepository_Block(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to in-memory database: %v", err)
+	}
+
+	err = db.AutoMigrate(&models.User{}, &models.Follower{}, &models.Block{}, &models.FollowRequest{})
+	if err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	repo := NewRepository(db, client)
+	ctx := context.Background()
+
+	user := &models.User{Name: "User", Email: "user@example.com", Nickname: "user"}
+	other := &models.User{Name: "Other", Email: "other@example.com", Nickname: "other"}
+	assert.NoError(t, db.Create(user).Error)
+	assert.NoError(t, db.Create(other).Error)
+
+	// Ambos usuarios se siguen mutuamente antes del bloqueo
+	_, err = repo.Follow(ctx, other.ID, user.ID)
+	assert.NoError(t, err)
+	_, err = repo.Follow(ctx, user.ID, other.ID)
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.Block(ctx, other.ID, user.ID))
+	// Bloquear de nuevo no tiene efecto
+	assert.NoError(t, repo.Block(ctx, other.ID, user.ID))
+
+	// El bloqueo rompe el seguimiento en ambos sentidos
+	var follows int64
+	assert.NoError(t, db.Model(&models.Follower{}).Count(&follows).Error)
+	assert.Equal(t, int64(0), follows)
+	for _, id := range []string{user.ID, other.ID} {
+		var stored models.User
+		assert.NoError(t, db.First(&stored, "id = ?", id).Error)
+		assert.Equal(t, 0, stored.Followers)
+		assert.Equal(t, 0, stored.Following)
+
+		members, err := client.SMembers(ctx, "following:"+id).Result()
+		assert.NoError(t, err)
+		assert.Empty(t, members)
+		members, err = client.SMembers(ctx, "followers:"+id).Result()
+		assert.NoError(t, err)
+		assert.Empty(t, members)
+	}
+
+	// Se refleja en Redis y se publica un unfollow por cada sentido
+	blocking, err := client.SIsMember(ctx, "blocking:"+user.ID, other.ID).Result()
+	assert.NoError(t, err)
+	assert.True(t, blocking)
+	blockedBy, err := client.SIsMember(ctx, "blocked_by:"+other.ID, user.ID).Result()
+	assert.NoError(t, err)
+	assert.True(t, blockedBy)
+
+	entries, err := client.XRange(ctx, followStream, "-", "+").Result()
+	assert.NoError(t, err)
+	unfollows := 0
+	for _, entry := range entries {
+		if entry.Values["type"] == unfollowEvent {
+			unfollows++
+		}
+	}
+	assert.Equal(t, 2, unfollows)
+
+	// Ninguno de los dos puede volver a seguir al otro
+	_, err = repo.Follow(ctx, other.ID, user.ID)
+	assert.ErrorIs(t, err, models.ErrBlocked)
+	_, err = repo.Follow(ctx, user.ID, other.ID)
+	assert.ErrorIs(t, err, models.ErrBlocked)
+
+	assert.NoError(t, repo.Unblock(ctx, other.ID, user.ID))
+	blocking, err = client.SIsMember(ctx, "blocking:"+user.ID, other.ID).Result()
+	assert.NoError(t, err)
+	assert.False(t, blocking)
+	blockedBy, err = client.SIsMember(ctx, "blocked_by:"+other.ID, user.ID).Result()
+	assert.NoError(t, err)
+	assert.False(t, blockedBy)
+
+	assert.ErrorIs(t, repo.Block(ctx, user.ID, user.ID), models.ErrSelfAction)
+}
+
+func TestRepository_Search(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to in-memory database: %v", err)
+	}
+
+	err = db.AutoMigrate(&models.User{})
+	if err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	// Sin Redis la búsqueda se resuelve con SQLite
+	repo := NewRepository(db, redis.NewClient(&redis.Options{}))
+
+	maria, err := repo.Create(context.Background(), &dto.CreateUser{Name: "María Gómez", Email: "maria@example.com", Nickname: "mgomez"})
+	assert.NoError(t, err)
+	_, err = repo.Create(context.Background(), &dto.CreateUser{Name: "Mario Ruiz", Email: "mario@example.com", Nickname: "mruiz"})
+	assert.NoError(t, err)
+	assert.Equal(t, "maria gomez mgomez", maria.SearchName)
+
+	users, err := repo.Search(context.Background(), &dto.UserSearchQuery{Q: "maria"})
+	assert.NoError(t, err)
+	if assert.Len(t, users, 1) {
+		assert.Equal(t, maria.ID, users[0].ID)
+	}
+
+	users, err = repo.Search(context.Background(), &dto.UserSearchQuery{Q: "GÓMEZ"})
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+
+	users, err = repo.Search(context.Background(), &dto.UserSearchQuery{Q: "@mar"})
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+
+	// Los comodines de LIKE se buscan como texto
+	users, err = repo.Search(context.Background(), &dto.UserSearchQuery{Q: "%"})
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func TestRankSuggestions(t *testing.T) {
+	// Listas de seguidos de los usuarios a los que sigue "me"
+	candidates := [][]string{
+		{"ana", "luis", "me"},
+		{"luis", "pedro", "blocked"},
+		{"luis", "pedro", "ana", "followed"},
+	}
+	excluded := map[string]struct{}{"me": {}, "followed": {}, "blocked": {}}
+
+	suggestions := rankSuggestions(candidates, excluded, 2)
+	assert.Equal(t, []cachedSuggestion{
+		{ID: "luis", Mutuals: 3},
+		{ID: "ana", Mutuals: 2},
+	}, suggestions)
+
+	assert.Empty(t, rankSuggestions(nil, excluded, 10))
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"user_service/internal/domain/models"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Los bloqueos se reflejan en Redis en blocking:<id> (usuarios bloqueados por
+// id) y blocked_by:<id> (usuarios que bloquearon a id), y los silencios en
+// muting:<id>, para que tweets-service y timeline-service los consulten.
+
+// Block bloquea a id por parte de userID y elimina el seguimiento y las
+// solicitudes pendientes en ambos sentidos. Bloquear de nuevo no tiene efecto.
+func (r *repository) Block(ctx context.Context, id, userID string) error {
+	if id == userID {
+		return models.ErrSelfAction
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.User{}, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrUserNotFound
+			}
+			return fmt.Errorf("error al obtener el usuario: %w", err)
+		}
+
+		block := &models.Block{}
+		if err := tx.Where(models.Block{UserID: userID, BlockedID: id}).FirstOrCreate(block).Error; err != nil {
+			return fmt.Errorf("error al crear el bloqueo: %w", err)
+		}
+
+		pipe := r.redis.TxPipeline()
+		pipe.SAdd(ctx, fmt.Sprintf("blocking:%s", userID), id)
+		pipe.SAdd(ctx, fmt.Sprintf("blocked_by:%s", id), userID)
+
+		// Un bloqueo rompe el seguimiento en ambos sentidos
+		if err := r.removeFollow(ctx, tx, pipe, id, userID); err != nil {
+			return err
+		}
+		if err := r.removeFollow(ctx, tx, pipe, userID, id); err != nil {
+			return err
+		}
+		if err := tx.Where("(user_id = ? AND follower_id = ?) OR (user_id = ? AND follower_id = ?)", id, userID, userID, id).
+			Delete(&models.FollowRequest{}).Error; err != nil {
+			return fmt.Errorf("error al eliminar las solicitudes de seguimiento: %w", err)
+		}
+
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("error al actualizar Redis: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *repository) Unblock(ctx context.Context, id, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND blocked_id = ?", userID, id).Delete(&models.Block{}).Error; err != nil {
+			return fmt.Errorf("error al eliminar el bloqueo: %w", err)
+		}
+
+		pipe := r.redis.TxPipeline()
+		pipe.SRem(ctx, fmt.Sprintf("blocking:%s", userID), id)
+		pipe.SRem(ctx, fmt.Sprintf("blocked_by:%s", id), userID)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("error al actualizar Redis: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Mute silencia a id para userID. Silenciar de nuevo no tiene efecto.
+func (r *repository) Mute(ctx context.Context, id, userID string) error {
+	if id == userID {
+		return models.ErrSelfAction
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.User{}, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrUserNotFound
+			}
+			return fmt.Errorf("error al obtener el usuario: %w", err)
+		}
+
+		mute := &models.Mute{}
+		if err := tx.Where(models.Mute{UserID: userID, MutedID: id}).FirstOrCreate(mute).Error; err != nil {
+			return fmt.Errorf("error al crear el silencio: %w", err)
+		}
+
+		if err := r.redis.SAdd(ctx, fmt.Sprintf("muting:%s", userID), id).Err(); err != nil {
+			return fmt.Errorf("error al actualizar Redis: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *repository) Unmute(ctx context.Context, id, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND muted_id = ?", userID, id).Delete(&models.Mute{}).Error; err != nil {
+			return fmt.Errorf("error al eliminar el silencio: %w", err)
+		}
+
+		if err := r.redis.SRem(ctx, fmt.Sprintf("muting:%s", userID), id).Err(); err != nil {
+			return fmt.Errorf("error al actualizar Redis: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// isBlocked indica si alguno de los dos usuarios bloqueó al otro.
+func (r *repository) isBlocked(tx *gorm.DB, userID, otherID string) (bool, error) {
+	var count int64
+	if err := tx.Model(&models.Block{}).
+		Where("(user_id = ? AND blocked_id = ?) OR (user_id = ? AND blocked_id = ?)", userID, otherID, otherID, userID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("error al verificar el bloqueo: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// removeFollow elimina el seguimiento de followerID a userID si existe,
+// actualizando los contadores y encolando los cambios de Redis en pipe.
+func (r *repository) removeFollow(ctx context.Context, tx *gorm.DB, pipe redis.Pipeliner, userID, followerID string) error {
+	result := tx.Where("user_id = ? AND follower_id = ?", userID, followerID).Delete(&models.Follower{})
+	if result.Error != nil {
+		return fmt.Errorf("error al eliminar el registro de seguimiento: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	if err := tx.Model(&models.User{}).Where("id = ?", userID).UpdateColumn("followers", gorm.Expr("followers - ?", 1)).Error; err != nil {
+		return fmt.Errorf("error al decrementar los seguidores: %w", err)
+	}
+
+	if err := tx.Model(&models.User{}).Where("id = ?", followerID).UpdateColumn("following", gorm.Expr("following - ?", 1)).Error; err != nil {
+		return fmt.Errorf("error al decrementar los seguidos: %w", err)
+	}
+
+	pipe.SRem(ctx, fmt.Sprintf("following:%s", followerID), userID)
+	pipe.SRem(ctx, fmt.Sprintf("followers:%s", userID), followerID)
+	publishFollowEvent(ctx, pipe, unfollowEvent, userID, followerID)
+
+	return nil
+}
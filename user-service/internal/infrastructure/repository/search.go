@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+	"user_service/internal/application/dto"
+	"user_service/internal/domain/models"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// userSearchKey es el índice lexicográfico de la búsqueda de usuarios: un
+	// sorted set con puntuación 0 y miembros "<término>\x00<id>", donde cada
+	// término es el nickname, el nombre completo o una de sus palabras
+	userSearchKey = "user_search"
+
+	defaultSearchSize = 10
+)
+
+// normalizeSearch pasa el texto a minúsculas y sin acentos ni prefijo @, para
+// que "maria" encuentre a "María Gómez".
+func normalizeSearch(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, text)
+	if err != nil {
+		result = text
+	}
+
+	return strings.Join(strings.Fields(strings.ToLower(strings.TrimPrefix(result, "@"))), " ")
+}
+
+// searchName es el texto con el que se busca al usuario en SQLite.
+func searchName(user *models.User) string {
+	return normalizeSearch(user.Name + " " + strings.TrimPrefix(user.Nickname, "@"))
+}
+
+// searchTerms devuelve los prefijos por los que se puede encontrar al usuario.
+func searchTerms(user *models.User) []string {
+	seen := make(map[string]struct{})
+	var terms []string
+
+	add := func(term string) {
+		if _, ok := seen[term]; ok || term == "" {
+			return
+		}
+		seen[term] = struct{}{}
+		terms = append(terms, term)
+	}
+
+	add(normalizeSearch(user.Nickname))
+	name := normalizeSearch(user.Name)
+	add(name)
+	for _, word := range strings.Fields(name) {
+		add(word)
+	}
+
+	return terms
+}
+
+func searchMembers(user *models.User) []interface{} {
+	terms := searchTerms(user)
+	members := make([]interface{}, len(terms))
+	for i, term := range terms {
+		members[i] = term + "\x00" + user.ID
+	}
+	return members
+}
+
+func indexUser(ctx context.Context, pipe redis.Pipeliner, user *models.User) {
+	members := searchMembers(user)
+	entries := make([]redis.Z, len(members))
+	for i, member := range members {
+		entries[i] = redis.Z{Member: member}
+	}
+	pipe.ZAdd(ctx, userSearchKey, entries...)
+}
+
+func unindexUser(ctx context.Context, pipe redis.Pipeliner, user *models.User) {
+	pipe.ZRem(ctx, userSearchKey, searchMembers(user)...)
+}
+
+// Search busca usuarios cuyo nickname o nombre empiece por query.Q usando el
+// índice de Redis y, si no alcanza para completar la página, completa con los
+// usuarios cuyo nombre contiene el texto en SQLite.
+func (r *repository) Search(ctx context.Context, query *dto.UserSearchQuery) ([]*models.User, error) {
+	size := query.Size
+	if size <= 0 {
+		size = defaultSearchSize
+	}
+
+	prefix := normalizeSearch(query.Q)
+	if prefix == "" {
+		return []*models.User{}, nil
+	}
+
+	// Cada usuario tiene varios términos, así que se leen más miembros de los necesarios
+	members, err := r.redis.ZRangeByLex(ctx, userSearchKey, &redis.ZRangeBy{
+		Min:   "[" + prefix,
+		Max:   "[" + prefix + "\xff",
+		Count: int64(size * 4),
+	}).Result()
+	if err != nil {
+		log.Printf("Error al buscar usuarios en Redis: %v", err)
+	}
+
+	var ids []string
+	seen := make(map[string]struct{})
+	for _, member := range members {
+		_, id, found := strings.Cut(member, "\x00")
+		if _, ok := seen[id]; !found || ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+		if len(ids) == size {
+			break
+		}
+	}
+
+	users := make([]*models.User, 0, size)
+	if len(ids) > 0 {
+		var found []*models.User
+		if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&found).Error; err != nil {
+			return nil, fmt.Errorf("error al obtener los usuarios: %w", err)
+		}
+
+		// Conservar el orden del índice
+		byID := make(map[string]*models.User, len(found))
+		for _, user := range found {
+			byID[user.ID] = user
+		}
+		for _, id := range ids {
+			if user, ok := byID[id]; ok {
+				users = append(users, user)
+			}
+		}
+	}
+
+	if len(users) == size {
+		return users, nil
+	}
+
+	// Completar con coincidencias en cualquier parte del nombre
+	db := r.db.WithContext(ctx).Where(`search_name LIKE ? ESCAPE '\'`, "%"+escapeLike(prefix)+"%")
+	if len(ids) > 0 {
+		db = db.Where("id NOT IN ?", ids)
+	}
+	var fallback []*models.User
+	if err := db.Order("followers DESC, id").Limit(size - len(users)).Find(&fallback).Error; err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+		}
+		return nil, fmt.Errorf("error al buscar los usuarios: %w", err)
+	}
+
+	return append(users, fallback...), nil
+}
+
+// escapeLike evita que % y _ del texto buscado actúen como comodines.
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(text)
+}
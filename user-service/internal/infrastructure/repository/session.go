@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+	"user_service/internal/domain/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CreateSession guarda la sesión como hash en sessions:<id>, que expira si no
+// se renueva, y la indexa en user_sessions:<id> del usuario.
+func (r *repository) CreateSession(ctx context.Context, session *models.Session, ttl time.Duration) error {
+	now := time.Now()
+	session.CreatedAt = now
+	session.LastSeenAt = now
+
+	key := fmt.Sprintf("sessions:%s", session.ID)
+
+	pipe := r.redis.TxPipeline()
+	pipe.HSet(ctx, key, map[string]interface{}{
+		"user_id":      session.UserID,
+		"device":       session.Device,
+		"created_at":   now.UnixMilli(),
+		"last_seen_at": now.UnixMilli(),
+	})
+	pipe.Expire(ctx, key, ttl)
+	pipe.SAdd(ctx, fmt.Sprintf("user_sessions:%s", session.UserID), session.ID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("error al crear la sesión: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	values, err := r.redis.HGetAll(ctx, fmt.Sprintf("sessions:%s", id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener la sesión: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, models.ErrSessionNotFound
+	}
+
+	return parseSession(id, values), nil
+}
+
+// TouchSession registra el uso de la sesión y renueva su expiración.
+func (r *repository) TouchSession(ctx context.Context, id string, ttl time.Duration) error {
+	key := fmt.Sprintf("sessions:%s", id)
+
+	// La sesión puede revocarse mientras se renueva; WATCH evita recrearla
+	err := r.redis.Watch(ctx, func(tx *redis.Tx) error {
+		exists, err := tx.Exists(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if exists == 0 {
+			return models.ErrSessionNotFound
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, "last_seen_at", time.Now().UnixMilli())
+			pipe.Expire(ctx, key, ttl)
+			return nil
+		})
+		return err
+	}, key)
+	if err == redis.TxFailedErr {
+		return models.ErrSessionNotFound
+	}
+
+	return err
+}
+
+// Sessions devuelve las sesiones activas del usuario, de la más reciente a la
+// más antigua. Las que ya expiraron se retiran del índice.
+func (r *repository) Sessions(ctx context.Context, userID string) ([]*models.Session, error) {
+	indexKey := fmt.Sprintf("user_sessions:%s", userID)
+
+	ids, err := r.redis.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener las sesiones: %w", err)
+	}
+
+	pipe := r.redis.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HGetAll(ctx, fmt.Sprintf("sessions:%s", id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("error al obtener las sesiones: %w", err)
+	}
+
+	sessions := make([]*models.Session, 0, len(ids))
+	expired := make([]interface{}, 0)
+	for i, cmd := range cmds {
+		if len(cmd.Val()) == 0 {
+			expired = append(expired, ids[i])
+			continue
+		}
+		sessions = append(sessions, parseSession(ids[i], cmd.Val()))
+	}
+
+	if len(expired) > 0 {
+		r.redis.SRem(ctx, indexKey, expired...)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+
+	return sessions, nil
+}
+
+func (r *repository) RevokeSession(ctx context.Context, userID, id string) error {
+	indexKey := fmt.Sprintf("user_sessions:%s", userID)
+
+	// Solo se pueden revocar las sesiones propias
+	owned, err := r.redis.SIsMember(ctx, indexKey, id).Result()
+	if err != nil {
+		return fmt.Errorf("error al obtener la sesión: %w", err)
+	}
+	if !owned {
+		return models.ErrSessionNotFound
+	}
+
+	pipe := r.redis.TxPipeline()
+	pipe.Del(ctx, fmt.Sprintf("sessions:%s", id))
+	pipe.SRem(ctx, indexKey, id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("error al revocar la sesión: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) RevokeSessions(ctx context.Context, userID string) error {
+	indexKey := fmt.Sprintf("user_sessions:%s", userID)
+
+	ids, err := r.redis.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		return fmt.Errorf("error al obtener las sesiones: %w", err)
+	}
+
+	pipe := r.redis.TxPipeline()
+	for _, id := range ids {
+		pipe.Del(ctx, fmt.Sprintf("sessions:%s", id))
+	}
+	pipe.Del(ctx, indexKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("error al revocar las sesiones: %w", err)
+	}
+
+	return nil
+}
+
+func parseSession(id string, values map[string]string) *models.Session {
+	createdAt, _ := strconv.ParseInt(values["created_at"], 10, 64)
+	lastSeenAt, _ := strconv.ParseInt(values["last_seen_at"], 10, 64)
+
+	return &models.Session{
+		ID:         id,
+		UserID:     values["user_id"],
+		Device:     values["device"],
+		CreatedAt:  time.UnixMilli(createdAt),
+		LastSeenAt: time.UnixMilli(lastSeenAt),
+	}
+}
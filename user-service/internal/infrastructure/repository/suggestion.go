@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+	"user_service/internal/domain/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// Las sugerencias se guardan ya ordenadas en suggestions:<id>. Caducan
+	// para recoger los cambios en el grafo que no afectan directamente al
+	// usuario, como los nuevos seguidos de sus seguidos
+	suggestionsTTL = time.Hour
+	maxSuggestions = 50
+	// maxSuggestionSources limita cuántos seguidos se recorren al calcular
+	maxSuggestionSources = 500
+
+	defaultSuggestionsSize = 10
+)
+
+func suggestionsKey(id string) string {
+	return fmt.Sprintf("suggestions:%s", id)
+}
+
+// cachedSuggestion es la representación de una sugerencia en Redis.
+type cachedSuggestion struct {
+	ID      string `json:"id"`
+	Mutuals int    `json:"mutuals"`
+}
+
+// Suggestions devuelve los usuarios sugeridos para id: los seguidos de sus
+// seguidos, ordenados por cuántos de sus seguidos los siguen. Si no hay
+// sugerencias guardadas se calculan en el momento.
+func (r *repository) Suggestions(ctx context.Context, id string, size int) ([]*models.Suggestion, error) {
+	if size <= 0 {
+		size = defaultSuggestionsSize
+	}
+
+	cached, err := r.cachedSuggestions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cached == nil {
+		if cached, err = r.refreshSuggestions(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(cached) == 0 {
+		return []*models.Suggestion{}, nil
+	}
+
+	// Las sugerencias guardadas pueden no reflejar aún los últimos seguimientos o bloqueos
+	members := make([]interface{}, len(cached))
+	for i, suggestion := range cached {
+		members[i] = suggestion.ID
+	}
+	pipe := r.redis.Pipeline()
+	following := pipe.SMIsMember(ctx, fmt.Sprintf("following:%s", id), members...)
+	blocking := pipe.SMIsMember(ctx, fmt.Sprintf("blocking:%s", id), members...)
+	blockedBy := pipe.SMIsMember(ctx, fmt.Sprintf("blocked_by:%s", id), members...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("error al filtrar las sugerencias: %w", err)
+	}
+
+	var ids []string
+	mutuals := make(map[string]int)
+	for i, suggestion := range cached {
+		if following.Val()[i] || blocking.Val()[i] || blockedBy.Val()[i] {
+			continue
+		}
+		ids = append(ids, suggestion.ID)
+		mutuals[suggestion.ID] = suggestion.Mutuals
+		if len(ids) == size {
+			break
+		}
+	}
+	if len(ids) == 0 {
+		return []*models.Suggestion{}, nil
+	}
+
+	var users []*models.User
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&users).Error; err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("operación cancelada por exceder el límite de tiempo")
+		}
+		return nil, fmt.Errorf("error al obtener los usuarios sugeridos: %w", err)
+	}
+
+	// Conservar el orden de las sugerencias; los usuarios eliminados se omiten
+	byID := make(map[string]*models.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+	suggestions := make([]*models.Suggestion, 0, len(ids))
+	for _, id := range ids {
+		if user, ok := byID[id]; ok {
+			suggestions = append(suggestions, &models.Suggestion{User: user, Mutuals: mutuals[id]})
+		}
+	}
+
+	return suggestions, nil
+}
+
+// RefreshSuggestions vuelve a calcular las sugerencias de id si ya estaban
+// guardadas. Las de los usuarios que no las consultaron se calculan al pedirlas.
+func (r *repository) RefreshSuggestions(ctx context.Context, id string) error {
+	exists, err := r.redis.Exists(ctx, suggestionsKey(id)).Result()
+	if err != nil {
+		return fmt.Errorf("error al consultar las sugerencias: %w", err)
+	}
+	if exists == 0 {
+		return nil
+	}
+
+	_, err = r.refreshSuggestions(ctx, id)
+	return err
+}
+
+// cachedSuggestions lee las sugerencias guardadas de id, o nil si no hay.
+func (r *repository) cachedSuggestions(ctx context.Context, id string) ([]cachedSuggestion, error) {
+	data, err := r.redis.Get(ctx, suggestionsKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener las sugerencias: %w", err)
+	}
+
+	cached := []cachedSuggestion{}
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, fmt.Errorf("error al deserializar las sugerencias: %w", err)
+	}
+
+	return cached, nil
+}
+
+// refreshSuggestions calcula y guarda las sugerencias de id a partir de los
+// conjuntos following:<id> de Redis.
+func (r *repository) refreshSuggestions(ctx context.Context, id string) ([]cachedSuggestion, error) {
+	pipe := r.redis.Pipeline()
+	following := pipe.SMembers(ctx, fmt.Sprintf("following:%s", id))
+	blocking := pipe.SMembers(ctx, fmt.Sprintf("blocking:%s", id))
+	blockedBy := pipe.SMembers(ctx, fmt.Sprintf("blocked_by:%s", id))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("error al obtener el grafo de seguimiento: %w", err)
+	}
+
+	// Tampoco se sugieren las cuentas a las que ya se envió una solicitud
+	var requested []string
+	if err := r.db.WithContext(ctx).Model(&models.FollowRequest{}).
+		Where("follower_id = ?", id).Pluck("user_id", &requested).Error; err != nil {
+		return nil, fmt.Errorf("error al obtener las solicitudes de seguimiento: %w", err)
+	}
+
+	excluded := map[string]struct{}{id: {}}
+	for _, members := range [][]string{following.Val(), blocking.Val(), blockedBy.Val(), requested} {
+		for _, member := range members {
+			excluded[member] = struct{}{}
+		}
+	}
+
+	sources := following.Val()
+	if len(sources) > maxSuggestionSources {
+		rand.Shuffle(len(sources), func(i, j int) {
+			sources[i], sources[j] = sources[j], sources[i]
+		})
+		sources = sources[:maxSuggestionSources]
+	}
+
+	pipe = r.redis.Pipeline()
+	cmds := make([]*redis.StringSliceCmd, len(sources))
+	for i, source := range sources {
+		cmds[i] = pipe.SMembers(ctx, fmt.Sprintf("following:%s", source))
+	}
+	if len(sources) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("error al obtener el grafo de seguimiento: %w", err)
+		}
+	}
+
+	candidates := make([][]string, len(cmds))
+	for i, cmd := range cmds {
+		candidates[i] = cmd.Val()
+	}
+	suggestions := rankSuggestions(candidates, excluded, maxSuggestions)
+
+	data, err := json.Marshal(suggestions)
+	if err != nil {
+		return nil, fmt.Errorf("error al serializar las sugerencias: %w", err)
+	}
+	if err := r.redis.Set(ctx, suggestionsKey(id), data, suggestionsTTL).Err(); err != nil {
+		return nil, fmt.Errorf("error al guardar las sugerencias: %w", err)
+	}
+
+	return suggestions, nil
+}
+
+// rankSuggestions cuenta en cuántas listas de seguidos aparece cada candidato
+// y devuelve los limit más repetidos, omitiendo los excluidos.
+func rankSuggestions(candidates [][]string, excluded map[string]struct{}, limit int) []cachedSuggestion {
+	counts := make(map[string]int)
+	for _, list := range candidates {
+		for _, candidate := range list {
+			if _, ok := excluded[candidate]; !ok {
+				counts[candidate]++
+			}
+		}
+	}
+
+	suggestions := make([]cachedSuggestion, 0, len(counts))
+	for candidate, count := range counts {
+		suggestions = append(suggestions, cachedSuggestion{ID: candidate, Mutuals: count})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Mutuals != suggestions[j].Mutuals {
+			return suggestions[i].Mutuals > suggestions[j].Mutuals
+		}
+		return suggestions[i].ID < suggestions[j].ID
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"strings"
 	"time"
 	"user_service/internal/domain/models"
 
@@ -218,6 +219,8 @@ func (s *Seeder) Seed() {
 		if err := s.redis.Set(context.Background(), key, userData, 0).Err(); err != nil {
 			log.Fatalf("Error al guardar usuario %s en Redis: %v", user.Email, err)
 		}
+		nickname := strings.ToLower(strings.TrimPrefix(user.Nickname, "@"))
+		s.redis.HSet(context.Background(), "nicknames", nickname, user.ID)
 
 		// Obtener una lista de otros usuarios (excluyendo al usuario actual)
 		var otherUsers []models.User
@@ -269,7 +272,7 @@ func (s *Seeder) Seed() {
 
 func (s *Seeder) Clean() {
 
-	for _, table := range []string{"followers", "users"} {
+	for _, table := range []string{"followers", "follow_requests", "blocks", "mutes", "users"} {
 		err := s.db.Exec("DELETE FROM " + table).Error
 		if err != nil {
 			log.Fatalf("Error al borrar el contenido de la tabla %s: %v", table, err)
@@ -279,6 +282,13 @@ func (s *Seeder) Clean() {
 
 	deleteKeysWithPrefix(context.Background(), s.redis, "users:")
 	deleteKeysWithPrefix(context.Background(), s.redis, "followers:")
+	deleteKeysWithPrefix(context.Background(), s.redis, "blocking:")
+	deleteKeysWithPrefix(context.Background(), s.redis, "blocked_by:")
+	deleteKeysWithPrefix(context.Background(), s.redis, "muting:")
+	deleteKeysWithPrefix(context.Background(), s.redis, "private_users")
+	deleteKeysWithPrefix(context.Background(), s.redis, "nicknames")
+	deleteKeysWithPrefix(context.Background(), s.redis, "user_search")
+	deleteKeysWithPrefix(context.Background(), s.redis, "suggestions:")
 }
 
 func redisUser(u *models.User) ([]byte, error) {
@@ -0,0 +1,5 @@
+package interfaces
+
+type Cron interface {
+	ProcessFollows()
+}
@@ -2,12 +2,37 @@ package interfaces
 
 import (
 	"context"
+	"time"
 	"user_service/internal/application/dto"
 	"user_service/internal/domain/models"
 )
 
 type UserRepository interface {
 	Create(ctx context.Context, user *dto.CreateUser) (*models.User, error)
-	Follow(ctx context.Context, id, followerID string) error
+	GetById(ctx context.Context, id string) (*models.User, error)
+	GetByNickname(ctx context.Context, nickname string) (*models.User, error)
+	Update(ctx context.Context, id string, user *dto.UpdateUser) (*models.User, error)
+	Delete(ctx context.Context, id string) error
+	SyncCache(ctx context.Context) error
+	Search(ctx context.Context, query *dto.UserSearchQuery) ([]*models.User, error)
+	Follow(ctx context.Context, id, followerID string) (bool, error)
 	Unfollow(ctx context.Context, id, followerID string) error
+	Followers(ctx context.Context, id string, query *dto.FollowQuery) ([]*models.User, string, error)
+	Following(ctx context.Context, id string, query *dto.FollowQuery) ([]*models.User, string, error)
+	FollowRequests(ctx context.Context, id string, query *dto.FollowQuery) ([]*models.User, string, error)
+	ApproveFollowRequest(ctx context.Context, id, followerID string) error
+	RejectFollowRequest(ctx context.Context, id, followerID string) error
+	Suggestions(ctx context.Context, id string, size int) ([]*models.Suggestion, error)
+	RefreshSuggestions(ctx context.Context, id string) error
+	Relationship(ctx context.Context, id, otherID string) (bool, bool, error)
+	Block(ctx context.Context, id, userID string) error
+	Unblock(ctx context.Context, id, userID string) error
+	Mute(ctx context.Context, id, userID string) error
+	Unmute(ctx context.Context, id, userID string) error
+	CreateSession(ctx context.Context, session *models.Session, ttl time.Duration) error
+	GetSession(ctx context.Context, id string) (*models.Session, error)
+	TouchSession(ctx context.Context, id string, ttl time.Duration) error
+	Sessions(ctx context.Context, userID string) ([]*models.Session, error)
+	RevokeSession(ctx context.Context, userID, id string) error
+	RevokeSessions(ctx context.Context, userID string) error
 }
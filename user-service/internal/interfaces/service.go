@@ -6,7 +6,31 @@ import (
 )
 
 type UserService interface {
-	Create(ctx context.Context, user *dto.CreateUser) (*dto.User, error)
-	Follow(ctx context.Context, id, followerID string) error
+	GetById(ctx context.Context, id string) (*dto.User, error)
+	GetByNickname(ctx context.Context, nickname string) (*dto.User, error)
+	Update(ctx context.Context, id string, user *dto.UpdateUser) (*dto.User, error)
+	Delete(ctx context.Context, id string) error
+	Search(ctx context.Context, query *dto.UserSearchQuery) ([]dto.Follower, error)
+	Follow(ctx context.Context, id, followerID string) (bool, error)
 	Unfollow(ctx context.Context, id, followerID string) error
+	Followers(ctx context.Context, id string, query *dto.FollowQuery) (*dto.FollowerPage, error)
+	Following(ctx context.Context, id string, query *dto.FollowQuery) (*dto.FollowerPage, error)
+	FollowRequests(ctx context.Context, id string, query *dto.FollowQuery) (*dto.FollowerPage, error)
+	ApproveFollowRequest(ctx context.Context, id, followerID string) error
+	RejectFollowRequest(ctx context.Context, id, followerID string) error
+	Suggestions(ctx context.Context, id string, query *dto.SuggestionQuery) ([]dto.Suggestion, error)
+	Relationship(ctx context.Context, id, otherID string) (*dto.Relationship, error)
+	Block(ctx context.Context, id, userID string) error
+	Unblock(ctx context.Context, id, userID string) error
+	Mute(ctx context.Context, id, userID string) error
+	Unmute(ctx context.Context, id, userID string) error
+}
+
+type AuthService interface {
+	Register(ctx context.Context, register *dto.Register) (*dto.User, error)
+	Login(ctx context.Context, login *dto.Login) (*dto.Token, error)
+	Refresh(ctx context.Context, refresh *dto.Refresh) (*dto.Token, error)
+	Sessions(ctx context.Context, userID, currentID string) ([]dto.Session, error)
+	RevokeSession(ctx context.Context, userID, id string) error
+	RevokeSessions(ctx context.Context, userID string) error
 }